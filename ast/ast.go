@@ -24,7 +24,7 @@ func (n *Literal) GetToken() token.Token { return n.Token }
 
 type Interporation struct {
 	Token token.Token
-	Value *Ident
+	Value Expression
 }
 
 func (n *Interporation) GetToken() token.Token { return n.Token }
@@ -4,7 +4,7 @@ import "github.com/ysugimoto/tender/token"
 
 type For struct {
 	Token    token.Token
-	Iterator *Ident
+	Iterator Expression
 	Arg1     *Ident
 	Arg2     *Ident
 	Block    []Node
@@ -28,3 +28,105 @@ type GroupedExpression struct {
 
 func (n *GroupedExpression) GetToken() token.Token { return n.Token }
 func (n *GroupedExpression) expression()           {}
+
+type ConditionalExpression struct {
+	Token       token.Token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (n *ConditionalExpression) GetToken() token.Token { return n.Token }
+func (n *ConditionalExpression) expression()           {}
+
+type CallExpression struct {
+	Token     token.Token
+	Function  *Ident
+	Arguments []Expression
+}
+
+func (n *CallExpression) GetToken() token.Token { return n.Token }
+func (n *CallExpression) expression()           {}
+
+type ListExpression struct {
+	Token    token.Token
+	Elements []Expression
+}
+
+func (n *ListExpression) GetToken() token.Token { return n.Token }
+func (n *ListExpression) expression()           {}
+
+type ObjectExpression struct {
+	Token token.Token
+	Items []*ObjectItem
+}
+
+func (n *ObjectExpression) GetToken() token.Token { return n.Token }
+func (n *ObjectExpression) expression()           {}
+
+// ObjectItem is a key-value pair of object literal.
+// Key is an *Ident for bare word key like "{ key = value }", and it is not treated as variable reference
+type ObjectItem struct {
+	Key   Expression
+	Value Expression
+}
+
+// ForExpression is a Terraform for-expression like `[for s in list : upper(s) if s != ""]`
+// or `{for k, v in map : k => v}`.
+// Key is only set for object result, and Grouping is true when the value is followed by "..."
+type ForExpression struct {
+	Token      token.Token
+	Arg1       *Ident
+	Arg2       *Ident
+	Collection Expression
+	Key        Expression
+	Value      Expression
+	Condition  Expression
+	Grouping   bool
+}
+
+func (n *ForExpression) GetToken() token.Token { return n.Token }
+func (n *ForExpression) expression()           {}
+
+// IndexExpression is an index access like `list[i]` or `map[key]`,
+// and attribute access like `list[i].name` which Index is a *String.
+// Optional is true for optional chaining like `list[i]?.name`
+type IndexExpression struct {
+	Token    token.Token
+	Left     Expression
+	Index    Expression
+	Optional bool
+}
+
+func (n *IndexExpression) GetToken() token.Token { return n.Token }
+func (n *IndexExpression) expression()           {}
+
+// TemplateExpression is a quoted string which contains template sequences like `"Hello, ${name}!"`,
+// Parts holds literals, interporations and controls as the same as the template nodes
+type TemplateExpression struct {
+	Token token.Token
+	Parts []Node
+}
+
+func (n *TemplateExpression) GetToken() token.Token { return n.Token }
+func (n *TemplateExpression) expression()           {}
+
+// SplatExpression applies Each accessors to every element of Left like `servers[*].name`.
+// Legacy is true for the attribute-only splat like `servers.*.name`
+type SplatExpression struct {
+	Token  token.Token
+	Left   Expression
+	Each   Expression
+	Legacy bool
+}
+
+func (n *SplatExpression) GetToken() token.Token { return n.Token }
+func (n *SplatExpression) expression()           {}
+
+// SplatElement is a placeholder of each element in the accessors of SplatExpression
+type SplatElement struct {
+	Token token.Token
+}
+
+func (n *SplatElement) GetToken() token.Token { return n.Token }
+func (n *SplatElement) expression()           {}
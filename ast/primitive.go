@@ -41,3 +41,10 @@ type Bool struct {
 
 func (n *Bool) GetToken() token.Token { return n.Token }
 func (n *Bool) expression()           {}
+
+type Null struct {
+	Token token.Token
+}
+
+func (n *Null) GetToken() token.Token { return n.Token }
+func (n *Null) expression()           {}
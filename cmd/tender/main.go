@@ -2,10 +2,11 @@ package main
 
 import (
 	"fmt"
-	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/ysugimoto/tender"
+	"github.com/ysugimoto/tender/stdlib"
 )
 
 func exitError(format string, args ...any) {
@@ -29,10 +30,21 @@ func main() {
 	}
 	defer fp.Close()
 
-	rendered, err := tender.New(fp).Render()
-	if err != nil {
+	// CLI can use all standard library functions
+	functions := stdlib.Merge(
+		stdlib.Strings(),
+		stdlib.Collections(),
+		stdlib.Encoding(),
+		stdlib.Crypto(),
+		stdlib.Network(),
+		stdlib.DateTime(),
+		stdlib.Conversions(),
+		stdlib.Filesystem(),
+	)
+	// Filesystem functions can read files only under the template directory
+	fsys := os.DirFS(filepath.Dir(file))
+	tmpl := tender.New(fp, tender.WithFunctions(functions), tender.WithFileSystem(fsys))
+	if err := tmpl.RenderTo(os.Stdout); err != nil {
 		exitError("Failed to execute template: %s", err.Error())
 	}
-
-	io.WriteString(os.Stdout, rendered) // nolint:errcheck
 }
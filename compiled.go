@@ -0,0 +1,92 @@
+package tender
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender/ast"
+	"github.com/ysugimoto/tender/lexer"
+	"github.com/ysugimoto/tender/parser"
+	"github.com/ysugimoto/tender/value"
+)
+
+// Compiled struct represents parsed template.
+// Compiled template only holds parsed AST nodes and default options, never be changed after compiling.
+// Therefore you can render the same compiled template many times, and from multiple goroutines concurrently.
+type Compiled struct {
+	nodes   []ast.Node
+	options []RenderOption
+}
+
+// Compile template from io.Reader stream.
+// Provided options are used as default options for each rendering
+func Compile(r io.Reader, opts ...RenderOption) (*Compiled, error) {
+	nodes, err := parser.New(lexer.New(r)).Parse()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &Compiled{
+		nodes:   nodes,
+		options: opts,
+	}, nil
+}
+
+// Compile template from template string
+func CompileString(tmpl string, opts ...RenderOption) (*Compiled, error) {
+	return Compile(strings.NewReader(tmpl), opts...)
+}
+
+// MustCompile is a helper function that panics if Compile() function raised an error.
+// Usage:
+// compiled := tender.MustCompile(tender.CompileString(tmpl))
+func MustCompile(c *Compiled, err error) *Compiled {
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Render the compiled template with provided variables.
+// Provided options are applied after the default options which are specified on compiling
+func (c *Compiled) Render(vars Variables, opts ...RenderOption) (string, error) {
+	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
+	defer pool.Put(buf)
+
+	buf.Reset()
+	if err := c.execute(buf, variablesToValue(vars), opts); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return buf.String(), nil
+}
+
+// Execute the compiled template with provided variables and write rendered output to the writer.
+// Output is written incrementally while rendering, so the writer may receive partial output when error occurred
+func (c *Compiled) Execute(w io.Writer, vars Variables, opts ...RenderOption) error {
+	return c.execute(w, variablesToValue(vars), opts)
+}
+
+func (c *Compiled) execute(w io.Writer, global value.Value, opts []RenderOption) error {
+	ctx := newRenderContext(global)
+	for i := range c.options {
+		c.options[i](&ctx.options)
+	}
+	for i := range opts {
+		opts[i](&ctx.options)
+	}
+	ctx.ctx = newRuntimeContext(ctx.options)
+
+	return ctx.execute(w, c.nodes)
+}
+
+// Convert variables to value.Value
+func variablesToValue(vars Variables) value.Value {
+	v := value.Value{}
+	for key, val := range vars {
+		v[key] = reflect.ValueOf(val)
+	}
+	return v
+}
@@ -0,0 +1,140 @@
+package tender
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompiledRender(t *testing.T) {
+	input := `
+%{~ for i, v in some_list ~}
+${v}
+%{~ endfor ~}
+  trailing literal`
+
+	compiled, err := CompileString(input)
+	if err != nil {
+		t.Errorf("Unexpected compile error\n %+v", err)
+		return
+	}
+
+	// Render multiple times and results must be the same
+	for i := 0; i < 3; i++ {
+		rendered, err := compiled.Render(Variables{"some_list": []string{"a", "b"}})
+		if err != nil {
+			t.Errorf("Unexpected render error\n %+v", err)
+			return
+		}
+		if diff := cmp.Diff("abtrailing literal", rendered); diff != "" {
+			t.Errorf("Rendered string mismatch at %d, diff=%s", i, diff)
+			return
+		}
+	}
+}
+
+func TestCompiledRenderWithOptions(t *testing.T) {
+	compiled := MustCompile(CompileString("${v}"))
+
+	tests := []struct {
+		name   string
+		opts   []RenderOption
+		expect string
+	}{
+		{name: "without escape", expect: "<b>"},
+		{name: "with escape", opts: []RenderOption{WithHtmlEscape()}, expect: "&lt;b&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := compiled.Render(Variables{"v": "<b>"}, tt.opts...)
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompiledConcurrentRender(t *testing.T) {
+	compiled := MustCompile(CompileString(`%{ for i, v in list }${v}-%{ endfor }${name}`))
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 100)
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			name := strconv.Itoa(i)
+			rendered, err := compiled.Render(Variables{
+				"list": []int{i, i},
+				"name": name,
+			})
+			if err != nil {
+				errs <- err.Error()
+				return
+			}
+			if expect := name + "-" + name + "-" + name; rendered != expect {
+				errs <- "expects " + expect + " but got " + rendered
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for e := range errs {
+		t.Errorf("Concurrent rendering failed: %s", e)
+	}
+}
+
+func BenchmarkCompiledRender(b *testing.B) {
+	input := `%{ for v in some_list ~}
+inside loop, ${v} is variable interporation.
+%{ endfor }
+%{ if (v == "v" && w == "w") || v != "x" }complicated condition%{endif}
+`
+
+	vars := Variables{
+		"some_list": []string{"foo"},
+		"v":         "v",
+		"w":         "w",
+	}
+	compiled := MustCompile(CompileString(input))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		compiled.Render(vars) // nolint:errcheck
+	}
+}
+
+type chunkWriter struct {
+	chunks []string
+}
+
+func (c *chunkWriter) Write(p []byte) (int, error) {
+	c.chunks = append(c.chunks, string(p))
+	return len(p), nil
+}
+
+func TestCompiledExecute(t *testing.T) {
+	compiled := MustCompile(CompileString(`%{ for i, v in list ~}
+${v}
+%{~ endfor }`))
+
+	w := &chunkWriter{}
+	if err := compiled.Execute(w, Variables{"list": []string{"a", "b", "c"}}); err != nil {
+		t.Errorf("Unexpected execute error\n %+v", err)
+		return
+	}
+	// Output should be written per interporation, not whole string at once
+	if diff := cmp.Diff([]string{"a", "b", "c"}, w.chunks); diff != "" {
+		t.Errorf("Written chunks mismatch, diff=%s", diff)
+	}
+}
@@ -0,0 +1,54 @@
+package tender
+
+import (
+	"context"
+	"io"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender/ast"
+	"github.com/ysugimoto/tender/value"
+)
+
+// renderContext struct holds the state of a single rendering.
+// This struct holds some state that global variables which is provided via caller,
+// assigning local variables and rendering options.
+// The context is created per rendering so compiled template could be rendered concurrently.
+type renderContext struct {
+	global value.Value
+	locals []value.Value
+	// ctx is passed to the functions which receive context.Context
+	ctx context.Context
+	// splat is the current element while evaluating the accessors of splat expression
+	splat reflect.Value
+
+	options
+}
+
+func newRenderContext(global value.Value) *renderContext {
+	return &renderContext{
+		global: global,
+		locals: []value.Value{},
+		ctx:    context.Background(),
+	}
+}
+
+// Render nodes and write rendered output to the writer
+func (c *renderContext) execute(w io.Writer, nodes []ast.Node) error {
+	out := newWriter(w)
+	if err := c.render(out, nodes); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(out.flush())
+}
+
+// Lookup variables from local variables in order from the innermost scope,
+// or global assigned variables if local variable is not found.
+func (c *renderContext) lookupVariable(name string) (reflect.Value, error) {
+	for i := len(c.locals) - 1; i >= 0; i-- {
+		if v, err := c.locals[i].Resolve(name); err == nil {
+			return v, nil
+		}
+	}
+	return c.global.Resolve(name)
+}
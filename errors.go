@@ -3,13 +3,25 @@ package tender
 import (
 	"fmt"
 
+	"github.com/pkg/errors"
 	"github.com/ysugimoto/tender/token"
+	"github.com/ysugimoto/tender/value"
 )
 
 // RenderError is kind of error type for rendering
 type RenderError struct {
 	Token   token.Token
 	Message string
+	// missing is true for the error which is raised by accessing missing data
+	missing bool
+	// static is true for the error which is caused by the template itself, not by the data.
+	// It is never captured by try() and can() like syntax error
+	static bool
+}
+
+// Is reports the error is caused by missing data for errors.Is(err, value.ErrMissingValue)
+func (r *RenderError) Is(target error) bool {
+	return r.missing && target == value.ErrMissingValue
 }
 
 func (r *RenderError) Error() string {
@@ -25,13 +37,28 @@ func UndefinedVariable(t token.Token, name string) *RenderError {
 	return &RenderError{
 		Token:   t,
 		Message: fmt.Sprintf(`Undefined variable "%s"`, name),
+		missing: true,
+	}
+}
+
+func UndefinedEnvironmentVariable(t token.Token, name string) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: fmt.Sprintf(`Environment variable "%s" is not specified`, name),
+		missing: true,
 	}
 }
 
 func NotIterable(t token.Token, name string) *RenderError {
+	if name == "" {
+		return &RenderError{
+			Token:   t,
+			Message: `For collection expression is not iterable, must be slice or map`,
+		}
+	}
 	return &RenderError{
 		Token:   t,
-		Message: fmt.Sprintf(`Variable "%s" is not iterable, must be slice or map`, name),
+		Message: fmt.Sprintf(`"%s" is not iterable, must be slice or map`, name),
 	}
 }
 
@@ -41,3 +68,109 @@ func UnexpectedType(t token.Token, actual, expect string) *RenderError {
 		Message: fmt.Sprintf(`Unexpected Type found "%s", expects "%s"`, actual, expect),
 	}
 }
+
+func DuplicateObjectKey(t token.Token, key string) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: fmt.Sprintf(`Duplicate object key "%s"`, key),
+	}
+}
+
+func NotIndexable(t token.Token, typ string) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: fmt.Sprintf(`Value of type "%s" could not be indexed, must be slice, map or struct`, typ),
+	}
+}
+
+func NullIndex(t token.Token) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: `Null value could not be indexed`,
+		missing: true,
+	}
+}
+
+func InvalidIndex(t token.Token, err error) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: fmt.Sprintf(`Invalid index: %s`, err.Error()),
+	}
+}
+
+func IndexOutOfRange(t token.Token, index, length int) *RenderError {
+	return ValueError(t, value.IndexOutOfRange(index, length))
+}
+
+// ValueError wraps the error which is raised in value package with the token position
+func ValueError(t token.Token, err *value.ValueError) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: err.Message,
+		missing: errors.Is(err, value.ErrMissingValue),
+	}
+}
+
+func UndefinedKey(t token.Token, key string) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: fmt.Sprintf(`Undefined key "%s"`, key),
+		missing: true,
+	}
+}
+
+func UndefinedField(t token.Token, name string) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: fmt.Sprintf(`Undefined field "%s"`, name),
+		missing: true,
+	}
+}
+
+func UndefinedFunction(t token.Token, name string) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: fmt.Sprintf(`Undefined function "%s"`, name),
+		static:  true,
+	}
+}
+
+func InvalidArgumentCount(t token.Token, name, expect string, actual int) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: fmt.Sprintf(`Function "%s" expects %s arguments, but %d arguments provided`, name, expect, actual),
+		static:  true,
+	}
+}
+
+func ArgumentConversionError(t token.Token, name string, index int, err error) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: fmt.Sprintf(`Invalid argument #%d for function "%s": %s`, index, name, err.Error()),
+	}
+}
+
+func FunctionError(t token.Token, name string, err error) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: fmt.Sprintf(`Function "%s" returns error: %s`, name, err.Error()),
+	}
+}
+
+func NoSucceededArgument(t token.Token, err error) *RenderError {
+	return &RenderError{
+		Token:   t,
+		Message: fmt.Sprintf(`Function "try" could not evaluate any arguments, last error: %s`, err.Error()),
+	}
+}
+
+// Report the error is raised by the data while evaluating expression, which is captured by try() and can().
+// Unknown errors which are not raised by tender are treated as static
+func isDynamicError(err error) bool {
+	var re *RenderError
+	if errors.As(err, &re) {
+		return !re.static
+	}
+	var ve *value.ValueError
+	return errors.As(err, &ve)
+}
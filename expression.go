@@ -1,19 +1,35 @@
 package tender
 
 import (
+	"bytes"
+	"os"
 	"reflect"
 
 	"github.com/pkg/errors"
 	"github.com/ysugimoto/tender/ast"
+	"github.com/ysugimoto/tender/token"
 	"github.com/ysugimoto/tender/value"
 )
 
 // Evaluate expression inside if condition
-func (t *Template) evaluateExpression(expr ast.Expression) (reflect.Value, error) {
+func (c *renderContext) evaluateExpression(expr ast.Expression) (reflect.Value, error) {
 	switch tt := expr.(type) {
 	case *ast.Ident:
-		v, err := t.lookupVariable(tt.Value)
+		// Identifier which is constructed only "[A-Z_]" references environment variable
+		if isEnvironmentVariable(tt.Value) {
+			v, ok := os.LookupEnv(tt.Value)
+			if !ok {
+				return value.Null, errors.WithStack(UndefinedEnvironmentVariable(tt.Token, tt.Value))
+			}
+			return reflect.ValueOf(v), nil
+		}
+		v, err := c.lookupVariable(tt.Value)
 		if err != nil {
+			// Point to the identifier for the error which is raised while resolving the variable path
+			var ve *value.ValueError
+			if errors.As(err, &ve) {
+				return value.Null, errors.WithStack(ValueError(tt.Token, ve))
+			}
 			return value.Null, errors.WithStack(err)
 		}
 		return v, nil
@@ -25,26 +41,53 @@ func (t *Template) evaluateExpression(expr ast.Expression) (reflect.Value, error
 		return reflect.ValueOf(tt.Value), nil
 	case *ast.Bool:
 		return reflect.ValueOf(tt.Value), nil
+	case *ast.Null:
+		return value.Null, nil
 
 	case *ast.PrefixExpression:
-		return t.evaluatePrefixExpression(tt)
+		return c.evaluatePrefixExpression(tt)
 	case *ast.InfixExpression:
-		return t.evaluateInfixExpression(tt)
+		return c.evaluateInfixExpression(tt)
 	case *ast.GroupedExpression:
-		return t.evaluateGroupedExpression(tt)
+		return c.evaluateGroupedExpression(tt)
+	case *ast.ConditionalExpression:
+		return c.evaluateConditionalExpression(tt)
+	case *ast.CallExpression:
+		return c.evaluateCallExpression(tt)
+	case *ast.ListExpression:
+		return c.evaluateListExpression(tt)
+	case *ast.ObjectExpression:
+		return c.evaluateObjectExpression(tt)
+	case *ast.ForExpression:
+		return c.evaluateForExpression(tt)
+	case *ast.IndexExpression:
+		return c.evaluateIndexExpression(tt)
+	case *ast.SplatExpression:
+		return c.evaluateSplatExpression(tt)
+	case *ast.SplatElement:
+		return c.splat, nil
+	case *ast.TemplateExpression:
+		return c.evaluateTemplateExpression(tt)
 	}
 
 	return value.Null, errors.WithStack(&RenderError{
 		Token:   expr.GetToken(),
 		Message: "Unexpected expression found",
+		static:  true,
 	})
 }
 
-func (t *Template) evaluatePrefixExpression(expr *ast.PrefixExpression) (reflect.Value, error) {
-	right, err := t.evaluateExpression(expr.Right)
+func (c *renderContext) evaluatePrefixExpression(expr *ast.PrefixExpression) (reflect.Value, error) {
+	right, err := c.evaluateExpression(expr.Right)
 	if err != nil {
 		return value.Null, errors.WithStack(err)
 	}
+	if value.IsNull(right) {
+		return value.Null, errors.WithStack(&RenderError{
+			Token:   expr.GetToken(),
+			Message: `Unexpected "` + expr.Operator + `" prefix operator for null`,
+		})
+	}
 	switch expr.Operator {
 	case "!":
 		switch right.Type().Kind() {
@@ -76,16 +119,22 @@ func (t *Template) evaluatePrefixExpression(expr *ast.PrefixExpression) (reflect
 		return value.Null, errors.WithStack(&RenderError{
 			Token:   expr.GetToken(),
 			Message: `Unexpected prefix operator "` + expr.Operator + `" found`,
+			static:  true,
 		})
 	}
 }
 
-func (t *Template) evaluateInfixExpression(expr *ast.InfixExpression) (reflect.Value, error) {
-	left, err := t.evaluateExpression(expr.Left)
+func (c *renderContext) evaluateInfixExpression(expr *ast.InfixExpression) (reflect.Value, error) {
+	// Left expression of "??" could be missing, it should not raise an error
+	if expr.Operator == "??" {
+		return c.evaluateCoalesceExpression(expr)
+	}
+
+	left, err := c.evaluateExpression(expr.Left)
 	if err != nil {
 		return value.Null, errors.WithStack(err)
 	}
-	right, err := t.evaluateExpression(expr.Right)
+	right, err := c.evaluateExpression(expr.Right)
 	if err != nil {
 		return value.Null, errors.WithStack(err)
 	}
@@ -126,50 +175,471 @@ func (t *Template) evaluateInfixExpression(expr *ast.InfixExpression) (reflect.V
 			return value.Null, errors.WithStack(err)
 		}
 		return reflect.ValueOf(cmp), nil
-	case "&&":
+	case "&&", "||":
 		if !value.IsBool(left) {
-			if err != nil {
-				return value.Null, errors.WithStack(
-					UnexpectedType(expr.Left.GetToken(), left.Type().Kind().String(), "bool"),
-				)
-			}
+			return value.Null, errors.WithStack(
+				UnexpectedType(expr.Left.GetToken(), value.TypeName(left), "bool"),
+			)
 		}
 		if !value.IsBool(right) {
-			if err != nil {
-				return value.Null, errors.WithStack(
-					UnexpectedType(expr.Right.GetToken(), right.Type().Kind().String(), "bool"),
-				)
-			}
+			return value.Null, errors.WithStack(
+				UnexpectedType(expr.Right.GetToken(), value.TypeName(right), "bool"),
+			)
 		}
-		return reflect.ValueOf(left.Bool() && right.Bool()), nil
-	case "||":
-		if !value.IsBool(left) {
-			if err != nil {
-				return value.Null, errors.WithStack(
-					UnexpectedType(expr.Left.GetToken(), left.Type().Kind().String(), "bool"),
-				)
-			}
-		}
-		if !value.IsBool(right) {
-			if err != nil {
-				return value.Null, errors.WithStack(
-					UnexpectedType(expr.Right.GetToken(), right.Type().Kind().String(), "bool"),
-				)
-			}
+		if expr.Operator == "&&" {
+			return reflect.ValueOf(reflect.Indirect(left).Bool() && reflect.Indirect(right).Bool()), nil
 		}
-		return reflect.ValueOf(left.Bool() || right.Bool()), nil
+		return reflect.ValueOf(reflect.Indirect(left).Bool() || reflect.Indirect(right).Bool()), nil
+	case "+", "-", "*", "/", "%":
+		return c.evaluateArithmeticExpression(expr, left, right)
 	default:
 		return value.Null, errors.WithStack(&RenderError{
 			Token:   expr.GetToken(),
 			Message: `Unexpected operation "` + expr.Operator + `" found`,
+			static:  true,
+		})
+	}
+}
+
+// Evaluate "??" operator, right expression is evaluated only when left is null or its data is missing
+// like undefined variable, key, field or index
+func (c *renderContext) evaluateCoalesceExpression(expr *ast.InfixExpression) (reflect.Value, error) {
+	left, err := c.evaluateExpression(expr.Left)
+	if err != nil && !errors.Is(err, value.ErrMissingValue) {
+		return value.Null, errors.WithStack(err)
+	}
+	if err == nil && !value.IsNull(left) {
+		return left, nil
+	}
+
+	right, err := c.evaluateExpression(expr.Right)
+	if err != nil {
+		return value.Null, errors.WithStack(err)
+	}
+	return right, nil
+}
+
+func (c *renderContext) evaluateArithmeticExpression(expr *ast.InfixExpression, left, right reflect.Value) (reflect.Value, error) {
+	var calculate func(left, right reflect.Value) (reflect.Value, error)
+	switch expr.Operator {
+	case "+":
+		calculate = value.Add
+	case "-":
+		calculate = value.Subtract
+	case "*":
+		calculate = value.Multiply
+	case "/":
+		calculate = value.Divide
+	default:
+		calculate = value.Modulo
+	}
+
+	v, err := calculate(left, right)
+	if err != nil {
+		// Arithmetic error like division by zero should point to the operator position
+		return value.Null, errors.WithStack(&RenderError{
+			Token:   expr.GetToken(),
+			Message: err.Error(),
+		})
+	}
+	return v, nil
+}
+
+func (c *renderContext) evaluateGroupedExpression(expr *ast.GroupedExpression) (reflect.Value, error) {
+	v, err := c.evaluateExpression(expr.Right)
+	if err != nil {
+		return value.Null, errors.WithStack(err)
+	}
+	return v, nil
+}
+
+func (c *renderContext) evaluateConditionalExpression(expr *ast.ConditionalExpression) (reflect.Value, error) {
+	cond, err := c.evaluateExpression(expr.Condition)
+	if err != nil {
+		return value.Null, errors.WithStack(err)
+	}
+
+	truthy, err := value.IsThuthy(cond)
+	if err != nil {
+		return value.Null, errors.WithStack(&RenderError{
+			Token:   expr.Condition.GetToken(),
+			Message: err.Error(),
 		})
 	}
+
+	// Only evaluate selected expression, another one is never evaluated
+	// so that unselected expression never raises an error
+	var v reflect.Value
+	if truthy {
+		v, err = c.evaluateExpression(expr.Consequence)
+	} else {
+		v, err = c.evaluateExpression(expr.Alternative)
+	}
+	if err != nil {
+		return value.Null, errors.WithStack(err)
+	}
+	return v, nil
+}
+
+func (c *renderContext) evaluateCallExpression(expr *ast.CallExpression) (reflect.Value, error) {
+	// try() and can() evaluate arguments lazily to capture errors, so they are not registered functions
+	switch expr.Function.Value {
+	case "try":
+		return c.evaluateTryFunction(expr)
+	case "can":
+		return c.evaluateCanFunction(expr)
+	}
+
+	fn, ok := c.functions[expr.Function.Value]
+	if !ok {
+		return value.Null, errors.WithStack(UndefinedFunction(expr.Token, expr.Function.Value))
+	}
+
+	args := make([]reflect.Value, len(expr.Arguments))
+	for i := range expr.Arguments {
+		v, err := c.evaluateExpression(expr.Arguments[i])
+		if err != nil {
+			return value.Null, errors.WithStack(err)
+		}
+		args[i] = v
+	}
+
+	return fn.call(c.ctx, expr.Token, args)
+}
+
+// Evaluate try() function like Terraform, returns the first argument which is evaluated without dynamic error.
+// Static errors like undefined function are not captured
+func (c *renderContext) evaluateTryFunction(expr *ast.CallExpression) (reflect.Value, error) {
+	if len(expr.Arguments) == 0 {
+		return value.Null, errors.WithStack(InvalidArgumentCount(expr.Token, "try", "at least 1", 0))
+	}
+
+	var lastErr error
+	for i := range expr.Arguments {
+		v, err := c.evaluateExpression(expr.Arguments[i])
+		if err == nil {
+			return v, nil
+		}
+		if !isDynamicError(err) {
+			return value.Null, errors.WithStack(err)
+		}
+		lastErr = err
+	}
+	return value.Null, errors.WithStack(NoSucceededArgument(expr.Token, lastErr))
+}
+
+// Evaluate can() function like Terraform, returns true if the argument is evaluated without dynamic error
+func (c *renderContext) evaluateCanFunction(expr *ast.CallExpression) (reflect.Value, error) {
+	if len(expr.Arguments) != 1 {
+		return value.Null, errors.WithStack(InvalidArgumentCount(expr.Token, "can", "1", len(expr.Arguments)))
+	}
+
+	if _, err := c.evaluateExpression(expr.Arguments[0]); err != nil {
+		if !isDynamicError(err) {
+			return value.Null, errors.WithStack(err)
+		}
+		return reflect.ValueOf(false), nil
+	}
+	return reflect.ValueOf(true), nil
+}
+
+// Evaluate list literal to []any
+func (c *renderContext) evaluateListExpression(expr *ast.ListExpression) (reflect.Value, error) {
+	list := make([]any, len(expr.Elements))
+	for i := range expr.Elements {
+		v, err := c.evaluateExpression(expr.Elements[i])
+		if err != nil {
+			return value.Null, errors.WithStack(err)
+		}
+		if v.IsValid() {
+			list[i] = v.Interface()
+		}
+	}
+	return reflect.ValueOf(list), nil
+}
+
+// Evaluate object literal to map[string]any
+func (c *renderContext) evaluateObjectExpression(expr *ast.ObjectExpression) (reflect.Value, error) {
+	object := make(map[string]any, len(expr.Items))
+	for _, item := range expr.Items {
+		key, err := c.evaluateObjectKey(item.Key)
+		if err != nil {
+			return value.Null, errors.WithStack(err)
+		}
+		if _, ok := object[key]; ok {
+			return value.Null, errors.WithStack(DuplicateObjectKey(item.Key.GetToken(), key))
+		}
+
+		v, err := c.evaluateExpression(item.Value)
+		if err != nil {
+			return value.Null, errors.WithStack(err)
+		}
+		object[key] = nil
+		if v.IsValid() {
+			object[key] = v.Interface()
+		}
+	}
+	return reflect.ValueOf(object), nil
+}
+
+// Bare word key is used as it is, otherwise evaluated value must be a primitive
+func (c *renderContext) evaluateObjectKey(expr ast.Expression) (string, error) {
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Value, nil
+	}
+
+	v, err := c.evaluateExpression(expr)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return toObjectKey(expr.GetToken(), v)
+}
+
+// Object key must be a primitive value, and it is converted to string
+func toObjectKey(t token.Token, v reflect.Value) (string, error) {
+	v = unwrapElement(v)
+	if !v.IsValid() {
+		return "", errors.WithStack(UnexpectedType(t, "null", "string"))
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Bool:
+		return value.ToString(v), nil
+	default:
+		if value.IsNumeric(v) {
+			return value.ToString(v), nil
+		}
+		return "", errors.WithStack(UnexpectedType(t, v.Kind().String(), "string"))
+	}
+}
+
+// Evaluate for-expression to []any for list result, or map[string]any for object result.
+// Unlike for control, single argument is assigned the element value like Terraform
+func (c *renderContext) evaluateForExpression(expr *ast.ForExpression) (reflect.Value, error) {
+	collection, err := c.evaluateExpression(expr.Collection)
+	if err != nil {
+		return value.Null, errors.WithStack(err)
+	}
+
+	list := []any{}
+	object := map[string]any{}
+	ok, err := iterate(collection, func(key, val reflect.Value) error {
+		local := value.Value{}
+		if expr.Arg2 != nil {
+			local[expr.Arg1.Value] = key
+			local[expr.Arg2.Value] = val
+		} else {
+			local[expr.Arg1.Value] = val
+		}
+
+		// Push local scoped values for this iteration
+		c.locals = append(c.locals, local)
+		defer func() {
+			c.locals = c.locals[0 : len(c.locals)-1]
+		}()
+
+		if expr.Condition != nil {
+			cond, err := c.evaluateExpression(expr.Condition)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			truthy, err := value.IsThuthy(cond)
+			if err != nil {
+				return errors.WithStack(&RenderError{
+					Token:   expr.Condition.GetToken(),
+					Message: err.Error(),
+				})
+			}
+			if !truthy {
+				return nil
+			}
+		}
+
+		v, err := c.evaluateExpression(expr.Value)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		var element any
+		if v.IsValid() {
+			element = v.Interface()
+		}
+
+		// List result
+		if expr.Key == nil {
+			list = append(list, element)
+			return nil
+		}
+
+		// Object result
+		k, err := c.evaluateExpression(expr.Key)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		name, err := toObjectKey(expr.Key.GetToken(), k)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if expr.Grouping {
+			group, _ := object[name].([]any) // nolint:errcheck
+			object[name] = append(group, element)
+			return nil
+		}
+		if _, ok := object[name]; ok {
+			return errors.WithStack(DuplicateObjectKey(expr.Key.GetToken(), name))
+		}
+		object[name] = element
+		return nil
+	})
+	if err != nil {
+		return value.Null, errors.WithStack(err)
+	}
+	if !ok {
+		return value.Null, errors.WithStack(NotIterable(expr.Collection.GetToken(), iteratorName(expr.Collection)))
+	}
+
+	if expr.Key == nil {
+		return reflect.ValueOf(list), nil
+	}
+	return reflect.ValueOf(object), nil
 }
 
-func (t *Template) evaluateGroupedExpression(expr *ast.GroupedExpression) (reflect.Value, error) {
-	v, err := t.evaluateExpression(expr.Right)
+// Evaluate index expression against map, slice or struct.
+// Index is converted to the map key type for map, and must be an integer for slice.
+// Optional index expression yields null instead of an error when the data is missing
+func (c *renderContext) evaluateIndexExpression(expr *ast.IndexExpression) (reflect.Value, error) {
+	left, err := c.evaluateExpression(expr.Left)
 	if err != nil {
 		return value.Null, errors.WithStack(err)
 	}
+	index, err := c.evaluateExpression(expr.Index)
+	if err != nil {
+		return value.Null, errors.WithStack(err)
+	}
+
+	v, err := indexValue(expr, left, index)
+	if err != nil {
+		if expr.Optional && errors.Is(err, value.ErrMissingValue) {
+			return value.Null, nil
+		}
+		return value.Null, errors.WithStack(err)
+	}
 	return v, nil
 }
+
+func indexValue(expr *ast.IndexExpression, left, index reflect.Value) (reflect.Value, error) {
+	if value.IsNull(left) {
+		return value.Null, errors.WithStack(NullIndex(expr.Token))
+	}
+	// Unwrap interface and pointer value like an element of []any
+	left = reflect.Indirect(reflect.ValueOf(left.Interface()))
+
+	var v reflect.Value
+	switch left.Kind() {
+	case reflect.Map:
+		key, err := value.Convert(index, left.Type().Key())
+		if err != nil {
+			return value.Null, errors.WithStack(InvalidIndex(expr.Index.GetToken(), err))
+		}
+		v = left.MapIndex(key)
+		if !v.IsValid() {
+			return value.Null, errors.WithStack(UndefinedKey(expr.Index.GetToken(), value.ToString(key)))
+		}
+	case reflect.Slice, reflect.Array:
+		i, err := value.Convert(index, reflect.TypeOf(0))
+		if err != nil {
+			return value.Null, errors.WithStack(InvalidIndex(expr.Index.GetToken(), err))
+		}
+		if n := int(i.Int()); n < 0 || n >= left.Len() {
+			return value.Null, errors.WithStack(IndexOutOfRange(expr.Index.GetToken(), n, left.Len()))
+		}
+		v = left.Index(int(i.Int()))
+	case reflect.Struct:
+		name, err := value.Convert(index, reflect.TypeOf(""))
+		if err != nil {
+			return value.Null, errors.WithStack(InvalidIndex(expr.Index.GetToken(), err))
+		}
+		// Only exported field could be accessed
+		field, found := value.LookupField(left, name.String())
+		if !found {
+			return value.Null, errors.WithStack(UndefinedField(expr.Index.GetToken(), name.String()))
+		}
+		if !field.IsValid() {
+			// Field is promoted through nil embedded pointer
+			return value.Null, errors.WithStack(NullIndex(expr.Token))
+		}
+		v = field
+	default:
+		return value.Null, errors.WithStack(NotIndexable(expr.Token, left.Kind().String()))
+	}
+
+	return reflect.ValueOf(v.Interface()), nil
+}
+
+// Evaluate splat expression for each element of the list and collect the results.
+// Null becomes an empty list and non-list value is treated as a single element list.
+// Null element is kept as null in the result
+func (c *renderContext) evaluateSplatExpression(expr *ast.SplatExpression) (reflect.Value, error) {
+	left, err := c.evaluateExpression(expr.Left)
+	if err != nil {
+		return value.Null, errors.WithStack(err)
+	}
+
+	var elements []reflect.Value
+	switch {
+	case value.IsNull(left):
+		elements = []reflect.Value{}
+	case left.Kind() == reflect.Slice, left.Kind() == reflect.Array:
+		elements = make([]reflect.Value, left.Len())
+		for i := range elements {
+			elements[i] = reflect.ValueOf(left.Index(i).Interface())
+		}
+	default:
+		elements = []reflect.Value{left}
+	}
+
+	// Restore outer element for the nested splat like "a[*].b[*].c"
+	outer := c.splat
+	defer func() {
+		c.splat = outer
+	}()
+
+	result := make([]any, len(elements))
+	for i := range elements {
+		if value.IsNull(elements[i]) {
+			continue
+		}
+		c.splat = elements[i]
+		v, err := c.evaluateExpression(expr.Each)
+		if err != nil {
+			return value.Null, errors.WithStack(err)
+		}
+		if v.IsValid() {
+			result[i] = v.Interface()
+		}
+	}
+	return reflect.ValueOf(result), nil
+}
+
+// Render string template parts into the string.
+// HTML escape is not applied to inside parts because the result is escaped on outer interporation
+func (c *renderContext) evaluateTemplateExpression(expr *ast.TemplateExpression) (reflect.Value, error) {
+	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
+	defer pool.Put(buf)
+
+	buf.Reset()
+
+	enableEscape := c.enableEscape
+	c.enableEscape = false
+	defer func() {
+		c.enableEscape = enableEscape
+	}()
+
+	w := newWriter(buf)
+	if err := c.render(w, expr.Parts); err != nil {
+		return value.Null, errors.WithStack(err)
+	}
+	if err := w.flush(); err != nil {
+		return value.Null, errors.WithStack(err)
+	}
+
+	return reflect.ValueOf(buf.String()), nil
+}
@@ -0,0 +1,117 @@
+package tender
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender/token"
+	"github.com/ysugimoto/tender/value"
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// function struct represents Go function which can be called from template.
+// Function signature is validated on registration, and arguments are validated on calling.
+type function struct {
+	name         string
+	fn           reflect.Value
+	returnsError bool
+	takesContext bool
+}
+
+// Create function from any Go function.
+// The function must return one value, or two values which second one is error.
+// If the first argument is context.Context, rendering context is passed to it on calling
+// so that the function can access per-rendering values like clock.
+func newFunction(name string, fn any) (*function, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return nil, fmt.Errorf(`Function "%s" is not a function, got %T`, name, fn)
+	}
+
+	t := v.Type()
+	f := &function{
+		name:         name,
+		fn:           v,
+		takesContext: t.NumIn() > 0 && t.In(0) == contextType,
+	}
+	switch {
+	case t.NumOut() == 1:
+		if t.Out(0) == errorType {
+			return nil, fmt.Errorf(`Function "%s" must return a value, not only an error`, name)
+		}
+		return f, nil
+	case t.NumOut() == 2 && t.Out(1) == errorType:
+		f.returnsError = true
+		return f, nil
+	default:
+		return nil, fmt.Errorf(`Function "%s" must return one value, or a value and an error`, name)
+	}
+}
+
+// Call function with evaluated arguments.
+// Token is used for the error position which points to the calling expression
+func (f *function) call(ctx context.Context, t token.Token, args []reflect.Value) (ret reflect.Value, err error) {
+	ft := f.fn.Type()
+	numIn := ft.NumIn()
+
+	// Context argument is not provided from template
+	offset := 0
+	if f.takesContext {
+		offset = 1
+		numIn--
+	}
+
+	// Validate arity
+	if ft.IsVariadic() {
+		if len(args) < numIn-1 {
+			return value.Null, errors.WithStack(InvalidArgumentCount(t, f.name, fmt.Sprintf("at least %d", numIn-1), len(args)))
+		}
+	} else if len(args) != numIn {
+		return value.Null, errors.WithStack(InvalidArgumentCount(t, f.name, fmt.Sprint(numIn), len(args)))
+	}
+
+	// Convert arguments to the function argument types
+	in := make([]reflect.Value, len(args)+offset)
+	if f.takesContext {
+		in[0] = reflect.ValueOf(ctx)
+	}
+	for i := range args {
+		var at reflect.Type
+		if ft.IsVariadic() && i >= numIn-1 {
+			at = ft.In(numIn - 1 + offset).Elem()
+		} else {
+			at = ft.In(i + offset)
+		}
+		v, err := value.Convert(args[i], at)
+		if err != nil {
+			return value.Null, errors.WithStack(ArgumentConversionError(t, f.name, i+1, err))
+		}
+		in[i+offset] = v
+	}
+
+	// Recover panic which is raised in the function, and treat it as an error
+	defer func() {
+		if r := recover(); r != nil {
+			ret = value.Null
+			err = errors.WithStack(FunctionError(t, f.name, fmt.Errorf("%v", r)))
+		}
+	}()
+
+	out := f.fn.Call(in)
+	if f.returnsError && !out[1].IsNil() {
+		return value.Null, errors.WithStack(FunctionError(t, f.name, out[1].Interface().(error))) // nolint:errcheck
+	}
+
+	ret = out[0]
+	// Unwrap interface value to be able to treat as concrete value
+	if ret.Kind() == reflect.Interface {
+		ret = ret.Elem()
+	}
+	return ret, nil
+}
@@ -0,0 +1,319 @@
+package tender
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestNewFunction(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      any
+		isError bool
+	}{
+		{name: "single return", fn: func(s string) string { return s }},
+		{name: "return with error", fn: func(s string) (string, error) { return s, nil }},
+		{name: "variadic", fn: func(s ...string) int { return len(s) }},
+		{name: "not a function", fn: "foo", isError: true},
+		{name: "no return", fn: func(s string) {}, isError: true},
+		{name: "only error return", fn: func(s string) error { return nil }, isError: true},
+		{name: "second return is not error", fn: func(s string) (string, string) { return s, s }, isError: true},
+		{name: "too many returns", fn: func(s string) (string, string, error) { return s, s, nil }, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newFunction("fn", tt.fn)
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestWithFunctionsPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expects panic for invalid function, but not panicked")
+		}
+	}()
+	WithFunctions(map[string]any{"invalid": 1})
+}
+
+func TestCallFunction(t *testing.T) {
+	functions := map[string]any{
+		"upper": strings.ToUpper,
+		"add":   func(a, b int) int { return a + b },
+		"half":  func(f float64) float64 { return f / 2 },
+		"join":  func(sep string, values ...string) string { return strings.Join(values, sep) },
+		"count": func(values []string) int { return len(values) },
+		"keys": func(m map[string]any) int {
+			return len(m)
+		},
+		"fail": func() (string, error) { return "", fmt.Errorf("something wrong") },
+		"boom": func() string { panic("boom") },
+		"any":  func(v any) any { return v },
+		"byte": func(b int8) int8 { return b },
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "basic call", input: `${upper("foo")}`, expect: "FOO"},
+		{name: "variable argument", input: `${upper(v)}`, expect: "FOO"},
+		{name: "int conversion", input: `${add(n, 2)}`, expect: "12"},
+		{name: "numeric string conversion", input: `${add("1", 2)}`, expect: "3"},
+		{name: "float conversion", input: `${half(n)}`, expect: "5"},
+		{name: "number to string conversion", input: `${upper(n)}`, expect: "10"},
+		{name: "variadic arguments", input: `${join("-", "a", "b", "c")}`, expect: "a-b-c"},
+		{name: "empty variadic arguments", input: `${join("-")}`, expect: ""},
+		{name: "slice conversion", input: `${count(list)}`, expect: "2"},
+		{name: "map argument", input: `${keys(m)}`, expect: "1"},
+		{name: "nested call", input: `${upper(join(",", v, "bar"))}`, expect: "FOO,BAR"},
+		{name: "call in expression", input: `${add(1, 2) * 2 == 6 ? "ok" : "ng"}`, expect: "ok"},
+		{name: "call in if condition", input: `%{ if upper(v) == "FOO" }ok%{ endif }`, expect: "ok"},
+		{name: "interface return", input: `${any(v)}`, expect: "foo"},
+		{name: "trailing comma", input: `${add(1, 2,)}`, expect: "3"},
+		{name: "pipeline", input: `${v | upper}`, expect: "FOO"},
+		{name: "pipeline with arguments", input: `${n | add(2) | half}`, expect: "6"},
+		{name: "pipeline after OR", input: `${false || v == "foo" | upper}`, expect: "TRUE"},
+		{name: "pipeline in grouped expression", input: `%{ if (v | upper) == "FOO" }ok%{ endif }`, expect: "ok"},
+		{name: "pipeline to undefined function", input: `${v | undefined}`, isError: true},
+		{name: "undefined function", input: `${undefined(v)}`, isError: true},
+		{name: "too few arguments", input: `${add(1)}`, isError: true},
+		{name: "too many arguments", input: `${add(1, 2, 3)}`, isError: true},
+		{name: "argument fits int8", input: `${byte(n * 12)}`, expect: "120"},
+		{name: "argument overflows int8", input: `${byte(300)}`, isError: true},
+		{name: "too few variadic arguments", input: `${join()}`, isError: true},
+		{name: "conversion error", input: `${add("foo", 1)}`, isError: true},
+		{name: "slice element conversion error", input: `${count(m)}`, isError: true},
+		{name: "function returns error", input: `${fail()}`, isError: true},
+		{name: "function panics", input: `${boom()}`, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := NewFromString(tt.input, WithFunctions(functions)).With(Variables{
+				"v":    "foo",
+				"n":    10,
+				"list": []any{"a", "b"},
+				"m":    map[string]any{"key": "value"},
+			}).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestCallFunctionErrorPosition(t *testing.T) {
+	opt := WithFunctions(map[string]any{
+		"add": func(a, b int) int { return a + b },
+	})
+	_, err := NewFromString("line1\n  ${ 1 + add(1, \"foo\") }", opt).Render()
+	if err == nil {
+		t.Errorf("Expects error, but got-nil")
+		return
+	}
+	var re *RenderError
+	if !errors.As(err, &re) {
+		t.Errorf("Expects RenderError, but got %T", err)
+		return
+	}
+	if diff := cmp.Diff([]int{2, 10}, []int{re.Token.Line, re.Token.Position}); diff != "" {
+		t.Errorf("Error position mismatch, diff=%s", diff)
+	}
+}
+
+func TestPipelineErrorPosition(t *testing.T) {
+	opt := WithFunctions(map[string]any{
+		"upper": strings.ToUpper,
+		"add":   func(a, b int) int { return a + b },
+	})
+	_, err := NewFromString("line1\n  ${ v | upper | add(1) }", opt).With(Variables{"v": "foo"}).Render()
+	if err == nil {
+		t.Errorf("Expects error, but got-nil")
+		return
+	}
+	var re *RenderError
+	if !errors.As(err, &re) {
+		t.Errorf("Expects RenderError, but got %T", err)
+		return
+	}
+	if diff := cmp.Diff([]int{2, 18}, []int{re.Token.Line, re.Token.Position}); diff != "" {
+		t.Errorf("Error position mismatch, diff=%s", diff)
+	}
+}
+
+func TestMergeFunctions(t *testing.T) {
+	compiled := MustCompile(CompileString(`${a()}${b()}`, WithFunctions(map[string]any{
+		"a": func() string { return "a" },
+		"b": func() string { return "b" },
+	})))
+
+	rendered, err := compiled.Render(nil, WithFunctions(map[string]any{
+		"b": func() string { return "B" },
+	}))
+	if err != nil {
+		t.Errorf("Unexpected render error\n %+v", err)
+		return
+	}
+	if diff := cmp.Diff("aB", rendered); diff != "" {
+		t.Errorf("Rendered string mismatch, diff=%s", diff)
+		return
+	}
+
+	// Default functions should not be modified by merging
+	rendered, err = compiled.Render(nil)
+	if err != nil {
+		t.Errorf("Unexpected render error\n %+v", err)
+		return
+	}
+	if diff := cmp.Diff("ab", rendered); diff != "" {
+		t.Errorf("Rendered string mismatch, diff=%s", diff)
+	}
+}
+
+func TestContextFunction(t *testing.T) {
+	fixed := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	functions := WithFunctions(map[string]any{
+		"year": func(ctx context.Context) int { return Now(ctx).Year() },
+		"add": func(ctx context.Context, days int) string {
+			return StartedAt(ctx).AddDate(0, 0, days).Format("2006-01-02")
+		},
+		"join": func(ctx context.Context, values ...string) string { return strings.Join(values, ",") },
+	})
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "context only", input: `${year()}`, expect: "2024"},
+		{name: "context with argument", input: `${add(3)}`, expect: "2024-04-04"},
+		{name: "context with variadic", input: `${join("a", "b")}`, expect: "a,b"},
+		{name: "context is not counted as argument", input: `${add()}`, isError: true},
+		{name: "too many arguments", input: `${year(1)}`, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := Render(tt.input, nil, functions, WithFixedClock(fixed))
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestTryAndCanFunction(t *testing.T) {
+	functions := WithFunctions(map[string]any{
+		"tonumber": strconv.Atoi,
+		"upper":    strings.ToUpper,
+	})
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "try first argument", input: `${try(config.name, "default")}`, expect: "tender"},
+		{name: "try undefined key", input: `${try(config.missing, "default")}`, expect: "default"},
+		{name: "try undefined variable", input: `${try(undefined.name, config.name)}`, expect: "tender"},
+		{name: "try function error", input: `${try(tonumber("x"), 0)}`, expect: "0"},
+		{name: "try type error", input: `${try(config.name + 1, -1)}`, expect: "-1"},
+		{name: "try null value is not an error", input: `[${try(null, "default")}]`, expect: "[]"},
+		{name: "try evaluates lazily", input: `${try("first", undefined)}`, expect: "first"},
+		{name: "try with pipeline", input: `${config.missing | try("default")}`, expect: "default"},
+		{name: "nested try", input: `${try(try(a, b), "c")}`, expect: "c"},
+		{name: "can succeeded", input: `${can(tonumber(config.port))}`, expect: "true"},
+		{name: "can failed", input: `${can(tonumber(config.name))}`, expect: "false"},
+		{name: "can in if control", input: `%{ if can(config.name) }yes%{ else }no%{ endif }`, expect: "yes"},
+		{name: "can with conditional", input: `${can(config.missing) ? "yes" : "no"}`, expect: "no"},
+		{name: "try all arguments failed", input: `${try(a, b)}`, isError: true},
+		{name: "try without arguments", input: `${try()}`, isError: true},
+		{name: "can with too many arguments", input: `${can(a, b)}`, isError: true},
+		{name: "undefined function is not captured by try", input: `${try(undefined(), "default")}`, isError: true},
+		{name: "undefined function is not captured by can", input: `${can(undefined())}`, isError: true},
+		{name: "argument count is not captured", input: `${try(upper("a", "b"), "default")}`, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := Variables{
+				"config": map[string]any{
+					"name": "tender",
+					"port": "8080",
+				},
+			}
+			rendered, err := Render(tt.input, vars, functions)
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestTryErrorPosition(t *testing.T) {
+	_, err := Render("line1\n${ try(a, b) }", nil)
+	if err == nil {
+		t.Errorf("Expects error but got nil")
+		return
+	}
+	var re *RenderError
+	if !errors.As(err, &re) {
+		t.Errorf("Expects RenderError, got %T", err)
+		return
+	}
+	if diff := cmp.Diff([]int{2, 4}, []int{re.Token.Line, re.Token.Position}); diff != "" {
+		t.Errorf("Error position mismatch, diff=%s", diff)
+	}
+}
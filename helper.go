@@ -54,13 +54,6 @@ func trimRightSpace(s string) string {
 	return s[:stop]
 }
 
-// strings.TrimRight implementation for *bytes.Buffer
-func trimRightSpaceBuffer(buf *bytes.Buffer) {
-	str := buf.String()
-	buf.Reset()
-	buf.WriteString(trimRightSpace(str))
-}
-
 // Check ident indicates environment variable reference.
 // If variable name is constructed only "[A-Z_]*", returns true
 func isEnvironmentVariable(ident string) bool {
@@ -4,8 +4,10 @@ import (
 	"bufio"
 	"bytes"
 	"io"
+	"strconv"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/ysugimoto/tender/token"
 )
@@ -19,7 +21,13 @@ const (
 	Control
 	ControlEnd
 	ControlEndTrim
+	InterporationStart
+	InterporationStartTrim
 	Interporation
+	Brace   // inside object literal braces in control or interporation
+	Quoted  // inside quoted string template in control or interporation
+	Heredoc // inside heredoc template in control or interporation
+	QuotedEnd
 )
 
 type Lexer struct {
@@ -32,6 +40,16 @@ type Lexer struct {
 	// file   string
 	isEOF  bool
 	states []State
+	// attribute is true when the previous token is DOT, then following identifier is a simple attribute name
+	attribute bool
+	// templates stores opening positions of quoted string and heredoc templates to report unterminated error
+	templates []template
+}
+
+type template struct {
+	line   int
+	index  int
+	marker string // closing marker, `"` for quoted string or identifier like "EOT" for heredoc
 }
 
 func New(r io.Reader) *Lexer {
@@ -93,6 +111,12 @@ func (l *Lexer) peekChar() rune {
 	return rune(b[0])
 }
 
+// Peek n bytes without forwarding, returns shorter string if input reaches EOF
+func (l *Lexer) peekString(n int) string {
+	b, _ := l.r.Peek(n) // nolint:errcheck
+	return string(b)
+}
+
 func (l *Lexer) NewLine() {
 	l.index = 0
 	l.line++
@@ -117,18 +141,31 @@ func (l *Lexer) NextToken() token.Token {
 		t := newToken(token.CONTROL_END, "~}", l.line, l.index-2)
 		t.RightTrim = true
 		return t
+	case InterporationStart:
+		l.replaceState(Interporation)
+		return newToken(token.INTERPORATION, "${", l.line, l.index-2)
+	case InterporationStartTrim:
+		l.replaceState(Interporation)
+		t := newToken(token.INTERPORATION, "${~", l.line, l.index-3)
+		t.LeftTrim = true
+		return t
+	case QuotedEnd:
+		l.popState()
+		l.templates = l.templates[0 : len(l.templates)-1]
+		return newToken(token.TEMPLATE_END, `"`, l.line, l.index-1)
 	}
 
 	// Following state must forward reading
 	defer l.readChar()
 
 	switch l.currentState() {
-	case Control:
+	case Control, Interporation, Brace:
+		// Interporation accepts the same expression as control
 		return l.nextControlToken()
-	case Interporation:
-		t := l.nextInterporationToken()
-		t.Position -= 2
-		return t
+	case Quoted:
+		return l.nextQuotedToken()
+	case Heredoc:
+		return l.nextHeredocToken()
 	default:
 		return l.nextToken()
 	}
@@ -181,13 +218,22 @@ func (l *Lexer) nextToken() token.Token {
 				goto CONT
 			case '{':
 				l.readChar()
-				if buf.Len() == 0 {
+				if l.peekChar() == '~' { // trim interporation
 					l.readChar()
-					t := l.nextInterporationToken()
-					t.Position -= 2
-					return t
+					if buf.Len() == 0 {
+						l.pushState(Interporation)
+						t := newToken(token.INTERPORATION, "${~", line, index)
+						t.LeftTrim = true
+						return t
+					}
+					l.pushState(InterporationStartTrim)
+				} else {
+					if buf.Len() == 0 {
+						l.pushState(Interporation)
+						return newToken(token.INTERPORATION, "${", line, index)
+					}
+					l.pushState(InterporationStart)
 				}
-				l.pushState(Interporation)
 				return newToken(token.LITERAL, buf.String(), line, index)
 			default:
 				return newToken(token.ILLEGAL, "Unexpected '$' character found", l.line, l.index)
@@ -212,33 +258,73 @@ func (l *Lexer) nextToken() token.Token {
 func (l *Lexer) nextControlToken() token.Token {
 	l.skipWhitespace()
 
+	attribute := l.attribute
+	l.attribute = false
+
 	index, line := l.index, l.line
 	switch l.char {
 	case '=':
-		if l.peekChar() == '=' {
+		switch l.peekChar() {
+		case '=': // "=="
 			l.readChar()
 			return newToken(token.EQUAL, "==", line, index)
+		case '>': // "=>"
+			l.readChar()
+			return newToken(token.ARROW, "=>", line, index)
 		}
-		return newToken(token.ILLEGAL, "=", l.line, l.index)
+		return newToken(token.ASSIGN, "=", line, index)
 	case '-':
 		return newToken(token.MINUS, "-", line, index)
-	case '}': // end control
+	case '+':
+		return newToken(token.PLUS, "+", line, index)
+	case '*':
+		return newToken(token.ASTERISK, "*", line, index)
+	case '/':
+		return newToken(token.SLASH, "/", line, index)
+	case '%':
+		return newToken(token.PERCENT, "%", line, index)
+	case '{': // start object literal, closing brace should not be treated as end control
+		l.pushState(Brace)
+		return newToken(token.LEFT_BRACE, "{", line, index)
+	case '}':
+		if l.currentState() == Brace { // end object literal
+			l.popState()
+			return newToken(token.RIGHT_BRACE, "}", line, index)
+		}
+		// end control
 		l.popState()
 		return newToken(token.CONTROL_END, "}", l.line, l.index)
+	case '[':
+		return newToken(token.LEFT_BRACKET, "[", line, index)
+	case ']':
+		return newToken(token.RIGHT_BRACKET, "]", line, index)
 	case '(':
 		return newToken(token.LEFT_PAREN, "(", line, index)
 	case ')':
 		return newToken(token.RIGHT_PAREN, ")", line, index)
 	case ',':
 		return newToken(token.COMMA, ",", line, index)
+	case '?':
+		switch {
+		case l.peekChar() == '?': // "??"
+			l.readChar()
+			return newToken(token.COALESCE, "??", line, index)
+		case isOptionalChain(string(l.char) + l.peekString(2)): // "?." like `list[i]?.name`
+			l.readChar()
+			l.attribute = isLetter(l.peekChar())
+			return newToken(token.OPTIONAL, "?.", line, index)
+		}
+		return newToken(token.QUESTION, "?", line, index)
+	case ':':
+		return newToken(token.COLON, ":", line, index)
 	case '"':
-		return newToken(token.STRING, l.readString(), line, index)
+		return l.readQuoted(line, index)
 	case '|':
 		if l.peekChar() == '|' { // "||"
 			l.readChar()
 			return newToken(token.OR, "||", line, index)
 		}
-		return newToken(token.ILLEGAL, "|", l.line, l.index)
+		return newToken(token.PIPE, "|", line, index)
 	case '&':
 		if l.peekChar() == '&' { // "&&"
 			l.readChar()
@@ -258,6 +344,8 @@ func (l *Lexer) nextControlToken() token.Token {
 		case '=': // "<="
 			l.readChar()
 			return newToken(token.LESS_THAN_EQUAL, "<=", line, index)
+		case '<': // heredoc like "<<EOT" or "<<-EOT"
+			return l.readHeredoc(line, index)
 		default:
 			return newToken(token.LESS_THAN, "<", line, index)
 		}
@@ -270,7 +358,7 @@ func (l *Lexer) nextControlToken() token.Token {
 			return newToken(token.NOT, "!", line, index)
 		}
 	case '~':
-		if l.peekChar() == '}' {
+		if l.peekChar() == '}' && l.currentState() != Brace {
 			l.readChar()
 			l.popState()
 			t := newToken(token.CONTROL_END, "~}", line, index)
@@ -284,12 +372,23 @@ func (l *Lexer) nextControlToken() token.Token {
 		return newToken(token.ILLEGAL, "", line, index)
 	default:
 		switch {
+		case isLetter(l.char) && attribute:
+			return newToken(token.IDENT, l.readName(), line, index)
+		case l.char == '.' && isLetter(l.peekChar()): // attribute access like `list[i].name`
+			l.attribute = true
+			return newToken(token.DOT, ".", line, index)
+		case l.char == '.' && l.peekChar() == '*': // legacy splat like `list.*.name`
+			return newToken(token.DOT, ".", line, index)
 		case isLetter(l.char):
 			literal, ok := l.readLiteral()
 			if !ok {
 				return newToken(token.ILLEGAL, "", line, index)
 			}
 			return newToken(token.LookupIdent(literal), literal, line, index)
+		case l.char == '.' && l.peekString(2) == "..":
+			l.readChar()
+			l.readChar()
+			return newToken(token.ELLIPSIS, "...", line, index)
 		case isDigit(l.char):
 			num := l.readNumber()
 
@@ -304,34 +403,6 @@ func (l *Lexer) nextControlToken() token.Token {
 	}
 }
 
-func (l *Lexer) nextInterporationToken() token.Token {
-	index, line := l.index, l.line
-
-	var literal string
-	for l.char != '}' {
-		switch l.char {
-		case 0x00:
-			return newToken(token.ILLEGAL, "", line, index)
-		default:
-			// Interporation only accepts identifier
-			// TODO: Maybe we implement filter process in the future
-			switch {
-			case isLetter(l.char):
-				lt, ok := l.readLiteral()
-				if !ok {
-					return newToken(token.ILLEGAL, string(l.char), line, index)
-				}
-				literal = lt
-			default:
-				return newToken(token.ILLEGAL, string(l.char), line, index)
-			}
-		}
-		l.readChar()
-	}
-	l.popState()
-	return newToken(token.INTERPORATION, literal, line, index)
-}
-
 func (l *Lexer) skipWhitespace() {
 	for l.char == ' ' || l.char == '\t' || l.char == '\r' {
 		l.readChar()
@@ -351,18 +422,34 @@ func (l *Lexer) readLiteral() (string, bool) {
 	buf.Reset()
 	buf.WriteString(l.readIdentifier())
 
-	// Read more neighbor digit, dot, underscore, left bracket
+	// Read more neighbor digit, dot, underscore, left bracket.
+	// Splat like "[*]" or ".*" is not a part of literal because following accessors are applied to each element
 	for {
 		peek := l.peekChar()
 		switch {
+		// Ellipsis is not a part of literal like "v..."
+		case peek == '.' && l.peekString(3) == "...":
+			return buf.String(), true
+		// Legacy splat like "a.*" is lexed as DOT and ASTERISK
+		case peek == '.' && l.peekString(2) == ".*":
+			return buf.String(), true
+		// Optional chaining as `?.name` or `?.[0]`, dynamic index is parsed as index expression
+		case peek == '?' && isOptionalChain(l.peekString(3)) && (isLetter(rune(l.peekString(3)[2])) || l.peekStaticIndex(2)):
+			l.readChar()
+			l.readChar()
+			buf.WriteString("?.")
+			if isLetter(l.peekChar()) {
+				l.readChar()
+				buf.WriteString(l.readIdentifier())
+			}
 		case isLetter(peek):
 			l.readChar()
 			buf.WriteString(l.readIdentifier())
-		case peek == '_' || peek == '.' || isDigit(l.char):
+		case peek == '_' || isDigit(peek):
 			l.readChar()
 			buf.WriteRune(l.char)
-		// Array or map indexing as `["..."]`
-		case peek == '[':
+		// Array or map indexing as `["..."]`, dynamic index is parsed as index expression
+		case peek == '[' && l.peekStaticIndex(0):
 			l.readChar()
 			buf.WriteRune(l.char)
 			switch {
@@ -387,6 +474,71 @@ func (l *Lexer) readLiteral() (string, bool) {
 	}
 }
 
+// Check characters are optional chaining like `?.name` or `?.[`
+func isOptionalChain(s string) bool {
+	return len(s) == 3 && s[:2] == "?." && (isLetter(rune(s[2])) || s[2] == '[')
+}
+
+// Check following characters after offset bytes are static index like `[0]` or `["key"]`
+// which are treated as a part of variable name
+func (l *Lexer) peekStaticIndex(offset int) bool {
+	// Peek n bytes after offset, returns shorter string if input reaches EOF
+	peek := func(n int) string {
+		s := l.peekString(offset + n)
+		if len(s) < offset {
+			return ""
+		}
+		return s[offset:]
+	}
+
+	b := peek(2)
+	if len(b) < 2 || b[0] != '[' {
+		return false
+	}
+
+	switch {
+	case b[1] == '"':
+		for n := 3; ; n++ {
+			b = peek(n)
+			if len(b) < n {
+				return false
+			}
+			if b[n-1] == '"' {
+				return peek(n+1) == b+"]"
+			}
+		}
+	case b[1] >= '0' && b[1] <= '9':
+		for n := 3; ; n++ {
+			b = peek(n)
+			if len(b) < n {
+				return false
+			}
+			if b[n-1] == ']' {
+				return true
+			}
+			if b[n-1] < '0' || b[n-1] > '9' {
+				return false
+			}
+		}
+	default:
+		return false
+	}
+}
+
+// Read attribute name which consists of letters, digits and underscore
+func (l *Lexer) readName() string {
+	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
+	defer pool.Put(buf)
+
+	buf.Reset()
+	buf.WriteRune(l.char)
+	for peek := l.peekChar(); isLetter(peek) || peek >= '0' && peek <= '9'; peek = l.peekChar() {
+		l.readChar()
+		buf.WriteRune(l.char)
+	}
+	return buf.String()
+}
+
 func (l *Lexer) readIdentifier() string {
 	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
 	defer pool.Put(buf)
@@ -418,6 +570,284 @@ func (l *Lexer) readString() string {
 	return buf.String()
 }
 
+// Read quoted string in expression.
+// Plain string is returned as STRING token with unescaped value, and string which contains
+// template sequence like "${" or "%{" is returned as TEMPLATE_START token with leading literal,
+// then following parts are lexed in Quoted state until the closing quote.
+func (l *Lexer) readQuoted(line, index int) token.Token {
+	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
+	defer pool.Put(buf)
+
+	buf.Reset()
+
+	l.readChar() // skip opening quote
+	for {
+		switch l.char {
+		case '"':
+			return newToken(token.STRING, buf.String(), line, index)
+		case 0x00: // EOF
+			return newToken(token.ILLEGAL, "Unterminated string", line, index)
+		case '\\':
+			i, n := l.index, l.line
+			if !l.readEscape(buf) {
+				return newToken(token.ILLEGAL, "Invalid escape sequence", n, i)
+			}
+		case '$', '%':
+			if hook, ok := l.readTemplateSequence(buf); ok {
+				l.templates = append(l.templates, template{line: line, index: index, marker: `"`})
+				l.pushState(Quoted)
+				l.pushState(hook)
+				return newToken(token.TEMPLATE_START, buf.String(), line, index)
+			}
+		default:
+			buf.WriteRune(l.char)
+		}
+		l.readChar()
+	}
+}
+
+// Lex inside string template after the template sequence is closed
+func (l *Lexer) nextQuotedToken() token.Token {
+	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
+	defer pool.Put(buf)
+
+	buf.Reset()
+
+	index, line := l.index, l.line
+	for {
+		switch l.char {
+		case '"':
+			if buf.Len() > 0 {
+				l.replaceState(QuotedEnd)
+				return newToken(token.LITERAL, buf.String(), line, index)
+			}
+			l.popState()
+			l.templates = l.templates[0 : len(l.templates)-1]
+			return newToken(token.TEMPLATE_END, `"`, line, index)
+		case 0x00: // EOF, report at the opening quote
+			quote := l.templates[len(l.templates)-1]
+			return newToken(token.ILLEGAL, "Unterminated string", quote.line, quote.index)
+		case '\\':
+			i, n := l.index, l.line
+			if !l.readEscape(buf) {
+				return newToken(token.ILLEGAL, "Invalid escape sequence", n, i)
+			}
+		case '$', '%':
+			if hook, ok := l.readTemplateSequence(buf); ok {
+				if buf.Len() > 0 {
+					l.pushState(hook)
+					return newToken(token.LITERAL, buf.String(), line, index)
+				}
+				return l.templateStartToken(hook, line, index)
+			}
+		default:
+			buf.WriteRune(l.char)
+		}
+		l.readChar()
+	}
+}
+
+// Read heredoc opening like "<<EOT" or "<<-EOT" which must be followed by newline.
+// Following lines are lexed in Heredoc state until the line which consists of the closing marker
+func (l *Lexer) readHeredoc(line, index int) token.Token {
+	l.readChar() // point to second "<"
+	literal := "<<"
+	if l.peekChar() == '-' { // indented heredoc
+		l.readChar()
+		literal += "-"
+	}
+	if !isLetter(l.peekChar()) {
+		return newToken(token.ILLEGAL, "Invalid heredoc marker", line, index)
+	}
+	l.readChar() // point to marker start
+	marker := l.readName()
+
+	if l.peekString(2) == "\r\n" {
+		l.readChar()
+	}
+	if l.peekChar() != '\n' {
+		return newToken(token.ILLEGAL, "Heredoc marker must be followed by newline", line, index)
+	}
+	l.readChar() // point to LF
+
+	l.templates = append(l.templates, template{line: line, index: index, marker: marker})
+	l.pushState(Heredoc)
+	return newToken(token.HEREDOC, literal+marker, line, index)
+}
+
+// Lex inside heredoc template. Heredoc does not have backslash escapes,
+// but template sequences and their escapes are the same as quoted string
+func (l *Lexer) nextHeredocToken() token.Token {
+	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
+	defer pool.Put(buf)
+
+	buf.Reset()
+
+	heredoc := l.templates[len(l.templates)-1]
+	index, line := l.index, l.line
+
+	// Closing marker line after the heredoc opening or the literal which ends with newline
+	if l.index == 1 {
+		if spaces, ok := isHeredocEnd(string(l.char)+l.peekString(heredocPeekSize), heredoc.marker); ok {
+			for i := 1; i < spaces+len(heredoc.marker); i++ {
+				l.readChar()
+			}
+			for l.peekChar() == ' ' || l.peekChar() == '\t' {
+				l.readChar()
+			}
+			// Trailing newline is a part of closing marker
+			if l.peekString(2) == "\r\n" {
+				l.readChar()
+			}
+			if l.peekChar() == '\n' {
+				l.readChar()
+			}
+			l.popState()
+			l.templates = l.templates[0 : len(l.templates)-1]
+			return newToken(token.TEMPLATE_END, heredoc.marker, line, index+spaces)
+		}
+	}
+
+	for {
+		switch l.char {
+		case '\n':
+			buf.WriteRune(l.char)
+			// Stop the literal before the closing marker line
+			if _, ok := isHeredocEnd(l.peekString(heredocPeekSize), heredoc.marker); ok {
+				return newToken(token.LITERAL, buf.String(), line, index)
+			}
+		case 0x00: // EOF, report at the heredoc opening
+			return newToken(token.ILLEGAL, "Unterminated heredoc", heredoc.line, heredoc.index)
+		case '$', '%':
+			if hook, ok := l.readTemplateSequence(buf); ok {
+				if buf.Len() > 0 {
+					l.pushState(hook)
+					return newToken(token.LITERAL, buf.String(), line, index)
+				}
+				return l.templateStartToken(hook, line, index)
+			}
+		default:
+			buf.WriteRune(l.char)
+		}
+		l.readChar()
+	}
+}
+
+// Maximum bytes to find the heredoc closing marker which may be indented
+const heredocPeekSize = 256
+
+// Check the line is the heredoc closing marker like "  EOT", and returns the number of leading whitespaces.
+// The marker must be placed on its own line, only trailing whitespaces are allowed before newline or EOF.
+// The line is peeked up to heredocPeekSize so EOF is recognized only when the peeked line is shorter than it
+func isHeredocEnd(line, marker string) (int, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(trimmed, marker) {
+		return 0, false
+	}
+	rest := strings.TrimLeft(trimmed[len(marker):], " \t")
+	switch {
+	case strings.HasPrefix(rest, "\n"), strings.HasPrefix(rest, "\r\n"):
+		return len(line) - len(trimmed), true
+	case rest == "" && len(line) < heredocPeekSize:
+		return len(line) - len(trimmed), true
+	default:
+		return 0, false
+	}
+}
+
+// Read template sequence start like "${", "${~", "%{" or "%{~" in quoted string,
+// and returns the hook state which emits its start token.
+// Escaped sequence like "$${" or "%%{" and lone sign character are written to the buffer as literal.
+func (l *Lexer) readTemplateSequence(buf *bytes.Buffer) (State, bool) {
+	sign := l.char
+	switch {
+	case l.peekString(2) == string(sign)+"{": // escaped sequence
+		l.readChar()
+		l.readChar()
+		buf.WriteRune(sign)
+		buf.WriteRune('{')
+		return Default, false
+	case l.peekChar() == '{':
+		l.readChar()
+		trim := l.peekChar() == '~'
+		if trim {
+			l.readChar()
+		}
+		switch {
+		case sign == '$' && trim:
+			return InterporationStartTrim, true
+		case sign == '$':
+			return InterporationStart, true
+		case trim:
+			return ControlStartTrim, true
+		default:
+			return ControlStart, true
+		}
+	default:
+		buf.WriteRune(sign)
+		return Default, false
+	}
+}
+
+// Emit template sequence start token immediately instead of the hook state
+func (l *Lexer) templateStartToken(hook State, line, index int) token.Token {
+	var t token.Token
+	switch hook {
+	case InterporationStartTrim:
+		l.pushState(Interporation)
+		t = newToken(token.INTERPORATION, "${~", line, index)
+		t.LeftTrim = true
+	case InterporationStart:
+		l.pushState(Interporation)
+		t = newToken(token.INTERPORATION, "${", line, index)
+	case ControlStartTrim:
+		l.pushState(Control)
+		t = newToken(token.CONTROL_START, "%{~", line, index)
+		t.LeftTrim = true
+	default:
+		l.pushState(Control)
+		t = newToken(token.CONTROL_START, "%{", line, index)
+	}
+	return t
+}
+
+// Read escape sequence in quoted string like `\n`, `\"` or `\u00e9`, returns false if invalid
+func (l *Lexer) readEscape(buf *bytes.Buffer) bool {
+	l.readChar() // point to escaped character
+	switch l.char {
+	case 'n':
+		buf.WriteRune('\n')
+	case 'r':
+		buf.WriteRune('\r')
+	case 't':
+		buf.WriteRune('\t')
+	case '"':
+		buf.WriteRune('"')
+	case '\\':
+		buf.WriteRune('\\')
+	case 'u', 'U':
+		size := 4
+		if l.char == 'U' {
+			size = 8
+		}
+		hex := l.peekString(size)
+		if len(hex) < size {
+			return false
+		}
+		code, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return false
+		}
+		for i := 0; i < size; i++ {
+			l.readChar()
+		}
+		buf.WriteRune(rune(code))
+	default:
+		return false
+	}
+	return true
+}
+
 func (l *Lexer) readNumber() string {
 	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
 	defer pool.Put(buf)
@@ -426,6 +856,10 @@ func (l *Lexer) readNumber() string {
 	buf.WriteRune(l.char)
 
 	for isDigit(l.peekChar()) {
+		// Ellipsis is not a part of number like "1..."
+		if l.peekString(3) == "..." {
+			break
+		}
 		l.readChar()
 		buf.WriteRune(l.char)
 	}
@@ -44,7 +44,9 @@ That's all, very simplified!
 		{Type: token.IDENT, Literal: "some_list", Line: 3, Position: 13},
 		{Type: token.CONTROL_END, Literal: "~}", Line: 3, Position: 23, RightTrim: true},
 		{Type: token.LITERAL, Literal: "\ninside loop, ", Line: 3, Position: 25},
-		{Type: token.INTERPORATION, Literal: "v", Line: 4, Position: 14},
+		{Type: token.INTERPORATION, Literal: "${", Line: 4, Position: 14},
+		{Type: token.IDENT, Literal: "v", Line: 4, Position: 16},
+		{Type: token.CONTROL_END, Literal: "}", Line: 4, Position: 17},
 		{Type: token.LITERAL, Literal: " is variable interporation.\n", Line: 4, Position: 18},
 		{Type: token.CONTROL_START, Literal: "%{", Line: 5, Position: 1},
 		{Type: token.ENDFOR, Literal: "endfor", Line: 5, Position: 4},
@@ -76,7 +78,9 @@ That's all, very simplified!
 		{Type: token.STRING, Literal: "v", Line: 11, Position: 12},
 		{Type: token.CONTROL_END, Literal: "}", Line: 11, Position: 16},
 		{Type: token.LITERAL, Literal: "\nif expression is also supported. Interporation is ", Line: 11, Position: 17},
-		{Type: token.INTERPORATION, Literal: "v", Line: 12, Position: 51},
+		{Type: token.INTERPORATION, Literal: "${", Line: 12, Position: 51},
+		{Type: token.IDENT, Literal: "v", Line: 12, Position: 53},
+		{Type: token.CONTROL_END, Literal: "}", Line: 12, Position: 54},
 		{Type: token.LITERAL, Literal: ".\n", Line: 12, Position: 55},
 
 		{Type: token.CONTROL_START, Literal: "%{", Line: 13, Position: 1},
@@ -188,7 +192,9 @@ func TestBeginningControl(t *testing.T) {
 				{Type: token.INT, Literal: "0", Line: 1, Position: 27},
 				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 28},
 
-				{Type: token.INTERPORATION, Literal: "i", Line: 1, Position: 29},
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 29},
+				{Type: token.IDENT, Literal: "i", Line: 1, Position: 31},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 32},
 
 				{Type: token.CONTROL_START, Literal: "%{", Line: 1, Position: 33},
 				{Type: token.ENDIF, Literal: "endif", Line: 1, Position: 35},
@@ -223,13 +229,400 @@ func TestVariableAccessSyntax(t *testing.T) {
 		{
 			input: "${a.b}",
 			expects: []token.Token{
-				{Type: token.INTERPORATION, Literal: "a.b", Line: 1, Position: 1},
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.IDENT, Literal: "a.b", Line: 1, Position: 3},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 6},
 			},
 		},
 		{
 			input: `${a["index"]}`,
 			expects: []token.Token{
-				{Type: token.INTERPORATION, Literal: `a["index"]`, Line: 1, Position: 1},
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.IDENT, Literal: `a["index"]`, Line: 1, Position: 3},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 13},
+			},
+		},
+		{
+			input: `${a[*].b * a.*.c}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.IDENT, Literal: "a", Line: 1, Position: 3},
+				{Type: token.LEFT_BRACKET, Literal: "[", Line: 1, Position: 4},
+				{Type: token.ASTERISK, Literal: "*", Line: 1, Position: 5},
+				{Type: token.RIGHT_BRACKET, Literal: "]", Line: 1, Position: 6},
+				{Type: token.DOT, Literal: ".", Line: 1, Position: 7},
+				{Type: token.IDENT, Literal: "b", Line: 1, Position: 8},
+				{Type: token.ASTERISK, Literal: "*", Line: 1, Position: 10},
+				{Type: token.IDENT, Literal: "a", Line: 1, Position: 12},
+				{Type: token.DOT, Literal: ".", Line: 1, Position: 13},
+				{Type: token.ASTERISK, Literal: "*", Line: 1, Position: 14},
+				{Type: token.DOT, Literal: ".", Line: 1, Position: 15},
+				{Type: token.IDENT, Literal: "c", Line: 1, Position: 16},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 17},
+			},
+		},
+		{
+			input: `${a.b[i].c["d"]}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.IDENT, Literal: `a.b`, Line: 1, Position: 3},
+				{Type: token.LEFT_BRACKET, Literal: "[", Line: 1, Position: 6},
+				{Type: token.IDENT, Literal: "i", Line: 1, Position: 7},
+				{Type: token.RIGHT_BRACKET, Literal: "]", Line: 1, Position: 8},
+				{Type: token.DOT, Literal: ".", Line: 1, Position: 9},
+				{Type: token.IDENT, Literal: "c", Line: 1, Position: 10},
+				{Type: token.LEFT_BRACKET, Literal: "[", Line: 1, Position: 11},
+				{Type: token.STRING, Literal: "d", Line: 1, Position: 12},
+				{Type: token.RIGHT_BRACKET, Literal: "]", Line: 1, Position: 15},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 16},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		l := NewFromString(tt.input)
+
+		for i, e := range tt.expects {
+			tok := l.NextToken()
+
+			if diff := cmp.Diff(e, tok); diff != "" {
+				t.Errorf(`Test[%d] failed, diff=%s`, i, diff)
+			}
+		}
+	}
+}
+
+func TestInterporationExpression(t *testing.T) {
+	tests := []struct {
+		input   string
+		expects []token.Token
+	}{
+		{
+			input: `${ v == "foo" }`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.IDENT, Literal: "v", Line: 1, Position: 4},
+				{Type: token.EQUAL, Literal: "==", Line: 1, Position: 6},
+				{Type: token.STRING, Literal: "foo", Line: 1, Position: 9},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 15},
+				{Type: token.EOF, Literal: "", Line: 1, Position: 16},
+			},
+		},
+		{
+			input: `a${~ -v ~}b`,
+			expects: []token.Token{
+				{Type: token.LITERAL, Literal: "a", Line: 1, Position: 1},
+				{Type: token.INTERPORATION, Literal: "${~", Line: 1, Position: 2, LeftTrim: true},
+				{Type: token.MINUS, Literal: "-", Line: 1, Position: 6},
+				{Type: token.IDENT, Literal: "v", Line: 1, Position: 7},
+				{Type: token.CONTROL_END, Literal: "~}", Line: 1, Position: 9, RightTrim: true},
+				{Type: token.LITERAL, Literal: "b", Line: 1, Position: 11},
+			},
+		},
+		{
+			input: `${base64encode(v2)}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.IDENT, Literal: "base64encode", Line: 1, Position: 3},
+				{Type: token.LEFT_PAREN, Literal: "(", Line: 1, Position: 15},
+				{Type: token.IDENT, Literal: "v2", Line: 1, Position: 16},
+				{Type: token.RIGHT_PAREN, Literal: ")", Line: 1, Position: 18},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 19},
+			},
+		},
+		{
+			input: `${a.b?.c ?? d}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.IDENT, Literal: "a.b?.c", Line: 1, Position: 3},
+				{Type: token.COALESCE, Literal: "??", Line: 1, Position: 10},
+				{Type: token.IDENT, Literal: "d", Line: 1, Position: 13},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 14},
+			},
+		},
+		{
+			input: `${l[i]?.x?.[0]}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.IDENT, Literal: "l", Line: 1, Position: 3},
+				{Type: token.LEFT_BRACKET, Literal: "[", Line: 1, Position: 4},
+				{Type: token.IDENT, Literal: "i", Line: 1, Position: 5},
+				{Type: token.RIGHT_BRACKET, Literal: "]", Line: 1, Position: 6},
+				{Type: token.OPTIONAL, Literal: "?.", Line: 1, Position: 7},
+				{Type: token.IDENT, Literal: "x", Line: 1, Position: 9},
+				{Type: token.OPTIONAL, Literal: "?.", Line: 1, Position: 10},
+				{Type: token.LEFT_BRACKET, Literal: "[", Line: 1, Position: 12},
+				{Type: token.INT, Literal: "0", Line: 1, Position: 13},
+				{Type: token.RIGHT_BRACKET, Literal: "]", Line: 1, Position: 14},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 15},
+			},
+		},
+		{
+			input: `${a?.["k"] ? 1 : 2}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.IDENT, Literal: `a?.["k"]`, Line: 1, Position: 3},
+				{Type: token.QUESTION, Literal: "?", Line: 1, Position: 12},
+				{Type: token.INT, Literal: "1", Line: 1, Position: 14},
+				{Type: token.COLON, Literal: ":", Line: 1, Position: 16},
+				{Type: token.INT, Literal: "2", Line: 1, Position: 18},
+			},
+		},
+		{
+			input: `${v == null || nullable}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.IDENT, Literal: "v", Line: 1, Position: 3},
+				{Type: token.EQUAL, Literal: "==", Line: 1, Position: 5},
+				{Type: token.NULL, Literal: "null", Line: 1, Position: 8},
+				{Type: token.OR, Literal: "||", Line: 1, Position: 13},
+				{Type: token.IDENT, Literal: "nullable", Line: 1, Position: 16},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 24},
+			},
+		},
+		{
+			input: `${v | upper || w}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.IDENT, Literal: "v", Line: 1, Position: 3},
+				{Type: token.PIPE, Literal: "|", Line: 1, Position: 5},
+				{Type: token.IDENT, Literal: "upper", Line: 1, Position: 7},
+				{Type: token.OR, Literal: "||", Line: 1, Position: 13},
+				{Type: token.IDENT, Literal: "w", Line: 1, Position: 16},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 17},
+			},
+		},
+		{
+			input: `${{a = ["b"]}}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.LEFT_BRACE, Literal: "{", Line: 1, Position: 3},
+				{Type: token.IDENT, Literal: "a", Line: 1, Position: 4},
+				{Type: token.ASSIGN, Literal: "=", Line: 1, Position: 6},
+				{Type: token.LEFT_BRACKET, Literal: "[", Line: 1, Position: 8},
+				{Type: token.STRING, Literal: "b", Line: 1, Position: 9},
+				{Type: token.RIGHT_BRACKET, Literal: "]", Line: 1, Position: 12},
+				{Type: token.RIGHT_BRACE, Literal: "}", Line: 1, Position: 13},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 14},
+				{Type: token.EOF, Literal: "", Line: 1, Position: 15},
+			},
+		},
+		{
+			input: `${{for k, v in m : k => v...}}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.LEFT_BRACE, Literal: "{", Line: 1, Position: 3},
+				{Type: token.FOR, Literal: "for", Line: 1, Position: 4},
+				{Type: token.IDENT, Literal: "k", Line: 1, Position: 8},
+				{Type: token.COMMA, Literal: ",", Line: 1, Position: 9},
+				{Type: token.IDENT, Literal: "v", Line: 1, Position: 11},
+				{Type: token.IN, Literal: "in", Line: 1, Position: 13},
+				{Type: token.IDENT, Literal: "m", Line: 1, Position: 16},
+				{Type: token.COLON, Literal: ":", Line: 1, Position: 18},
+				{Type: token.IDENT, Literal: "k", Line: 1, Position: 20},
+				{Type: token.ARROW, Literal: "=>", Line: 1, Position: 22},
+				{Type: token.IDENT, Literal: "v", Line: 1, Position: 25},
+				{Type: token.ELLIPSIS, Literal: "...", Line: 1, Position: 26},
+				{Type: token.RIGHT_BRACE, Literal: "}", Line: 1, Position: 29},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 30},
+			},
+		},
+		{
+			input: `${[1... 0.5]}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.LEFT_BRACKET, Literal: "[", Line: 1, Position: 3},
+				{Type: token.INT, Literal: "1", Line: 1, Position: 4},
+				{Type: token.ELLIPSIS, Literal: "...", Line: 1, Position: 5},
+				{Type: token.FLOAT, Literal: "0.5", Line: 1, Position: 9},
+				{Type: token.RIGHT_BRACKET, Literal: "]", Line: 1, Position: 12},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		l := NewFromString(tt.input)
+
+		for i, e := range tt.expects {
+			tok := l.NextToken()
+
+			if diff := cmp.Diff(e, tok); diff != "" {
+				t.Errorf(`Test[%d] failed, diff=%s`, i, diff)
+			}
+		}
+	}
+}
+
+func TestQuotedString(t *testing.T) {
+	tests := []struct {
+		input   string
+		expects []token.Token
+	}{
+		{
+			input: `${"a\"b\n\u00e9"}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.STRING, Literal: "a\"b\n\u00e9", Line: 1, Position: 3},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 17},
+				{Type: token.EOF, Literal: "", Line: 1, Position: 18},
+			},
+		},
+		{
+			input: `${"$${a} %%{b} $5"}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.STRING, Literal: "${a} %{b} $5", Line: 1, Position: 3},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 19},
+			},
+		},
+		{
+			input: `${"Hi ${name}!"}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.TEMPLATE_START, Literal: "Hi ", Line: 1, Position: 3},
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 7},
+				{Type: token.IDENT, Literal: "name", Line: 1, Position: 9},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 13},
+				{Type: token.LITERAL, Literal: "!", Line: 1, Position: 14},
+				{Type: token.TEMPLATE_END, Literal: `"`, Line: 1, Position: 15},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 16},
+				{Type: token.EOF, Literal: "", Line: 1, Position: 17},
+			},
+		},
+		{
+			input: `${"${a}%{ if b }x%{ endif }"}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.TEMPLATE_START, Literal: "", Line: 1, Position: 3},
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 4},
+				{Type: token.IDENT, Literal: "a", Line: 1, Position: 6},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 7},
+				{Type: token.CONTROL_START, Literal: "%{", Line: 1, Position: 8},
+				{Type: token.IF, Literal: "if", Line: 1, Position: 11},
+				{Type: token.IDENT, Literal: "b", Line: 1, Position: 14},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 16},
+				{Type: token.LITERAL, Literal: "x", Line: 1, Position: 17},
+				{Type: token.CONTROL_START, Literal: "%{", Line: 1, Position: 18},
+				{Type: token.ENDIF, Literal: "endif", Line: 1, Position: 21},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 27},
+				{Type: token.TEMPLATE_END, Literal: `"`, Line: 1, Position: 28},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 29},
+			},
+		},
+		{
+			input: `${"abc}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.ILLEGAL, Literal: "Unterminated string", Line: 1, Position: 3},
+			},
+		},
+		{
+			input: `${"a${b}c`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.TEMPLATE_START, Literal: "a", Line: 1, Position: 3},
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 5},
+				{Type: token.IDENT, Literal: "b", Line: 1, Position: 7},
+				{Type: token.CONTROL_END, Literal: "}", Line: 1, Position: 8},
+				{Type: token.ILLEGAL, Literal: "Unterminated string", Line: 1, Position: 3},
+			},
+		},
+		{
+			input: `${"\d"}`,
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.ILLEGAL, Literal: "Invalid escape sequence", Line: 1, Position: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		l := NewFromString(tt.input)
+
+		for i, e := range tt.expects {
+			tok := l.NextToken()
+
+			if diff := cmp.Diff(e, tok); diff != "" {
+				t.Errorf(`Test[%d] failed, diff=%s`, i, diff)
+			}
+		}
+	}
+}
+
+func TestHeredoc(t *testing.T) {
+	tests := []struct {
+		input   string
+		expects []token.Token
+	}{
+		{
+			input: "${<<-EOT\n  a${v}\n  EOT\n}",
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.HEREDOC, Literal: "<<-EOT", Line: 1, Position: 3},
+				{Type: token.LITERAL, Literal: "  a", Line: 2, Position: 1},
+				{Type: token.INTERPORATION, Literal: "${", Line: 2, Position: 4},
+				{Type: token.IDENT, Literal: "v", Line: 2, Position: 6},
+				{Type: token.CONTROL_END, Literal: "}", Line: 2, Position: 7},
+				{Type: token.LITERAL, Literal: "\n", Line: 2, Position: 8},
+				{Type: token.TEMPLATE_END, Literal: "EOT", Line: 3, Position: 3},
+				{Type: token.CONTROL_END, Literal: "}", Line: 4, Position: 1},
+				{Type: token.EOF, Literal: "", Line: 4, Position: 2},
+			},
+		},
+		{
+			input: "${ f(<<EOT\nEOTS \"$5\" $${v}\nEOT \t\n) }",
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.IDENT, Literal: "f", Line: 1, Position: 4},
+				{Type: token.LEFT_PAREN, Literal: "(", Line: 1, Position: 5},
+				{Type: token.HEREDOC, Literal: "<<EOT", Line: 1, Position: 6},
+				{Type: token.LITERAL, Literal: "EOTS \"$5\" ${v}\n", Line: 2, Position: 1},
+				{Type: token.TEMPLATE_END, Literal: "EOT", Line: 3, Position: 1},
+				{Type: token.RIGHT_PAREN, Literal: ")", Line: 4, Position: 1},
+				{Type: token.CONTROL_END, Literal: "}", Line: 4, Position: 3},
+			},
+		},
+		{
+			input: "${<<EOT\nEOT is here\nEOT, more\n  EOT)\nEOT\n}",
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.HEREDOC, Literal: "<<EOT", Line: 1, Position: 3},
+				{Type: token.LITERAL, Literal: "EOT is here\nEOT, more\n  EOT)\n", Line: 2, Position: 1},
+				{Type: token.TEMPLATE_END, Literal: "EOT", Line: 5, Position: 1},
+				{Type: token.CONTROL_END, Literal: "}", Line: 6, Position: 1},
+			},
+		},
+		{
+			input: "${<<EOT\na\r\nEOT\r\n}",
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.HEREDOC, Literal: "<<EOT", Line: 1, Position: 3},
+				{Type: token.LITERAL, Literal: "a\r\n", Line: 2, Position: 1},
+				{Type: token.TEMPLATE_END, Literal: "EOT", Line: 3, Position: 1},
+				{Type: token.CONTROL_END, Literal: "}", Line: 4, Position: 1},
+			},
+		},
+		{
+			input: "${<<EOT\nEOT}",
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.HEREDOC, Literal: "<<EOT", Line: 1, Position: 3},
+				{Type: token.ILLEGAL, Literal: "Unterminated heredoc", Line: 1, Position: 3},
+			},
+		},
+		{
+			input: "${<<EOT\nabc\n}",
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.HEREDOC, Literal: "<<EOT", Line: 1, Position: 3},
+				{Type: token.ILLEGAL, Literal: "Unterminated heredoc", Line: 1, Position: 3},
+			},
+		},
+		{
+			input: "${<<EOT }",
+			expects: []token.Token{
+				{Type: token.INTERPORATION, Literal: "${", Line: 1, Position: 1},
+				{Type: token.ILLEGAL, Literal: "Heredoc marker must be followed by newline", Line: 1, Position: 3},
 			},
 		},
 	}
@@ -1,9 +1,80 @@
 package tender
 
-type RenderOption func(t *Template)
+import (
+	"io/fs"
+	"time"
+)
+
+// options holds rendering options.
+// The options are resolved for each rendering so that the compiled template never holds mutable state.
+type options struct {
+	enableEscape bool
+	functions    map[string]*function
+	clock        func() time.Time
+	fileSystem   fs.FS
+}
+
+type RenderOption func(o *options)
 
 func WithHtmlEscape() RenderOption {
-	return func(t *Template) {
-		t.enableEscape = true
+	return func(o *options) {
+		o.enableEscape = true
+	}
+}
+
+// WithFunctions registers Go functions which can be called from template like "${ name(arg) }".
+// Function must return one value, or two values which second one is error.
+// Arguments are converted to the function argument types on calling, and returned error is treated as render error.
+// This option could be specified multiple times, and functions are merged.
+//
+// It panics if the value is not a function or the function has invalid return values,
+// the same as Funcs() of text/template package.
+func WithFunctions(functions map[string]any) RenderOption {
+	fns := make(map[string]*function, len(functions))
+	for name, fn := range functions {
+		f, err := newFunction(name, fn)
+		if err != nil {
+			panic(err)
+		}
+		fns[name] = f
+	}
+
+	return func(o *options) {
+		// Registered functions map is shared between renderings, so never modify it
+		if o.functions == nil {
+			o.functions = fns
+			return
+		}
+		merged := make(map[string]*function, len(o.functions)+len(fns))
+		for name, f := range o.functions {
+			merged[name] = f
+		}
+		for name, f := range fns {
+			merged[name] = f
+		}
+		o.functions = merged
+	}
+}
+
+// WithClock specifies the clock which is used for the current time in functions.
+// Functions can get the time via Now() and StartedAt() from the context.
+func WithClock(clock func() time.Time) RenderOption {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithFixedClock fixes the current time in functions, it is useful for reproducible rendering like golden tests.
+func WithFixedClock(t time.Time) RenderOption {
+	return WithClock(func() time.Time {
+		return t
+	})
+}
+
+// WithFileSystem provides the filesystem which functions can read files from.
+// Functions never access host files directly, so that you can sandbox templates with embed.FS or os.DirFS().
+func WithFileSystem(fsys fs.FS) RenderOption {
+	return func(o *options) {
+		o.fileSystem = fsys
 	}
 }
@@ -0,0 +1,213 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender/ast"
+	"github.com/ysugimoto/tender/token"
+)
+
+// Skip line feeds so that list and object literals can be written in multiple lines
+func (p *Parser) skipLF() {
+	for p.peekTokenIs(token.LF) {
+		p.NextToken()
+	}
+}
+
+// Parse list literal like `["a", "b"]`, elements are separated by comma and trailing comma is allowed
+func (p *Parser) parseListExpression() (ast.Expression, error) {
+	node := &ast.ListExpression{
+		Token:    p.curToken,
+		Elements: []ast.Expression{},
+	}
+
+	p.skipLF()
+	if p.peekTokenIs(token.FOR) {
+		return p.parseForExpression(node.Token, token.RIGHT_BRACKET)
+	}
+	for !p.peekTokenIs(token.RIGHT_BRACKET) {
+		p.NextToken() // point to element expression start
+		element, err := p.parseExpression(LOWEST)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		node.Elements = append(node.Elements, element)
+
+		p.skipLF()
+		if p.peekTokenIs(token.RIGHT_BRACKET) {
+			break
+		}
+		if !p.peekTokenIs(token.COMMA) {
+			return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.COMMA, token.RIGHT_BRACKET))
+		}
+		p.NextToken() // point to COMMA
+		p.skipLF()
+	}
+	p.NextToken() // point to RIGHT_BRACKET
+
+	return node, nil
+}
+
+// Parse object literal like `{ key = value, "k2": v }`.
+// Items are separated by comma or line feed, and both "=" and ":" are accepted as key-value separator
+func (p *Parser) parseObjectExpression() (ast.Expression, error) {
+	node := &ast.ObjectExpression{
+		Token: p.curToken,
+		Items: []*ast.ObjectItem{},
+	}
+
+	p.skipLF()
+	if p.peekTokenIs(token.FOR) {
+		return p.parseForExpression(node.Token, token.RIGHT_BRACE)
+	}
+	for !p.peekTokenIs(token.RIGHT_BRACE) {
+		p.NextToken() // point to key start
+		key, err := p.parseObjectKey()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		if !p.peekTokenIs(token.ASSIGN) && !p.peekTokenIs(token.COLON) {
+			return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.ASSIGN, token.COLON))
+		}
+		p.NextToken() // point to ASSIGN or COLON
+		p.NextToken() // point to value expression start
+
+		value, err := p.parseExpression(LOWEST)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		node.Items = append(node.Items, &ast.ObjectItem{
+			Key:   key,
+			Value: value,
+		})
+
+		switch {
+		case p.peekTokenIs(token.RIGHT_BRACE):
+			continue
+		case p.peekTokenIs(token.COMMA):
+			p.NextToken() // point to COMMA
+			p.skipLF()
+		case p.peekTokenIs(token.LF):
+			p.skipLF()
+		default:
+			return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.COMMA, token.RIGHT_BRACE))
+		}
+	}
+	p.NextToken() // point to RIGHT_BRACE
+
+	return node, nil
+}
+
+// Object key accepts bare word, string, or parenthesized expression to use evaluated value as key
+func (p *Parser) parseObjectKey() (ast.Expression, error) {
+	switch p.curToken.Type {
+	case token.IDENT:
+		// Bare word key must be a simple name, variable accessor is not allowed
+		if strings.ContainsAny(p.curToken.Literal, ".[") {
+			return nil, errors.WithStack(UnexpectedToken(p.curToken))
+		}
+		return p.parseIdent(), nil
+	case token.STRING:
+		return p.parseString(), nil
+	case token.TEMPLATE_START:
+		return p.parseTemplateExpression()
+	case token.LEFT_PAREN:
+		return p.parseGroupedExpression()
+	default:
+		return nil, errors.WithStack(UnexpectedToken(p.curToken, token.IDENT, token.STRING, token.LEFT_PAREN))
+	}
+}
+
+// Parse for-expression inside brackets or braces, peek token must point to FOR keyword.
+// Object result requires key expression with "=>", and value could be followed by "..." for grouping mode
+func (p *Parser) parseForExpression(open token.Token, closing token.TokenType) (*ast.ForExpression, error) {
+	node := &ast.ForExpression{
+		Token: open, // point to opening bracket or brace
+	}
+	isObject := closing == token.RIGHT_BRACE
+
+	p.NextToken() // point to FOR
+	p.NextToken() // point to first argument
+	if !p.curTokenIs(token.IDENT) {
+		return nil, errors.WithStack(UnexpectedToken(p.curToken, token.IDENT))
+	}
+	node.Arg1 = p.parseIdent()
+
+	// If next token is COMMA, for-expression has two arguments
+	if p.peekTokenIs(token.COMMA) {
+		p.NextToken() // point to COMMA
+		p.NextToken() // point to second argument
+		if !p.curTokenIs(token.IDENT) {
+			return nil, errors.WithStack(UnexpectedToken(p.curToken, token.IDENT))
+		}
+		node.Arg2 = p.parseIdent()
+	}
+
+	if !p.peekTokenIs(token.IN) {
+		return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.IN))
+	}
+	p.NextToken() // point to IN
+	p.NextToken() // point to collection expression start
+
+	collection, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	node.Collection = collection
+
+	p.skipLF()
+	if !p.peekTokenIs(token.COLON) {
+		return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.COLON))
+	}
+	p.NextToken() // point to COLON
+	p.skipLF()
+	p.NextToken() // point to key or value expression start
+
+	if isObject {
+		key, err := p.parseExpression(LOWEST)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		node.Key = key
+
+		p.skipLF()
+		if !p.peekTokenIs(token.ARROW) {
+			return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.ARROW))
+		}
+		p.NextToken() // point to ARROW
+		p.skipLF()
+		p.NextToken() // point to value expression start
+	}
+
+	value, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	node.Value = value
+
+	if isObject && p.peekTokenIs(token.ELLIPSIS) {
+		p.NextToken() // point to ELLIPSIS
+		node.Grouping = true
+	}
+
+	p.skipLF()
+	if p.peekTokenIs(token.IF) {
+		p.NextToken() // point to IF
+		p.NextToken() // point to condition expression start
+		condition, err := p.parseExpression(LOWEST)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		node.Condition = condition
+		p.skipLF()
+	}
+
+	if !p.peekTokenIs(closing) {
+		return nil, errors.WithStack(UnexpectedToken(p.peekToken, closing))
+	}
+	p.NextToken() // point to closing bracket or brace
+
+	return node, nil
+}
@@ -54,12 +54,13 @@ func (p *Parser) parseForControl() (*ast.For, error) {
 		return nil, errors.WithStack(UnexpectedToken(p.curToken, token.IN))
 	}
 
-	// Expect iterator
+	// Expect iterator, any expression which is evaluated to slice or map is accepted
 	p.NextToken()
-	if !p.curTokenIs(token.IDENT) {
-		return nil, errors.WithStack(UnexpectedToken(p.curToken, token.IDENT))
+	iterator, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, errors.WithStack(err)
 	}
-	node.Iterator = p.parseIdent()
+	node.Iterator = iterator
 
 	if !p.peekTokenIs(token.CONTROL_END) {
 		return nil, errors.WithStack(UnexpectedToken(p.curToken, token.CONTROL_END))
@@ -94,10 +95,11 @@ func (p *Parser) parseForControl() (*ast.For, error) {
 			}
 			blocks = append(blocks, control)
 		case token.INTERPORATION:
-			blocks = append(blocks, &ast.Interporation{
-				Token: p.curToken,
-				Value: p.parseIdent(),
-			})
+			interporation, err := p.parseInterporation()
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			blocks = append(blocks, interporation)
 		default:
 			return nil, errors.WithStack(UnexpectedToken(p.curToken))
 		}
@@ -189,13 +191,14 @@ func (p *Parser) parseIfControl() (*ast.If, error) {
 				node.End = t
 				goto OUT
 			default:
-				appendTarget(&ast.Literal{Token: p.curToken})
+				appendTarget(control)
 			}
 		case token.INTERPORATION:
-			appendTarget(&ast.Interporation{
-				Token: p.curToken,
-				Value: p.parseIdent(),
-			})
+			interporation, err := p.parseInterporation()
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			appendTarget(interporation)
 		default:
 			return nil, errors.WithStack(UnexpectedToken(p.curToken))
 		}
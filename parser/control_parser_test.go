@@ -153,7 +153,7 @@ func TestForControl(t *testing.T) {
 							},
 							Block: []ast.Node{
 								&ast.Interporation{
-									Token: token.Token{Literal: "w"},
+									Token: token.Token{Literal: "${"},
 									Value: &ast.Ident{
 										Token: token.Token{Literal: "w"},
 										Value: "w",
@@ -180,6 +180,70 @@ func TestForControl(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "expression iterator",
+			input: `%{for v in keys(m) }foo%{endfor}`,
+			expect: []ast.Node{
+				&ast.For{
+					Token: token.Token{Literal: "for"},
+					Iterator: &ast.CallExpression{
+						Token: token.Token{Literal: "keys"},
+						Function: &ast.Ident{
+							Token: token.Token{Literal: "keys"},
+							Value: "keys",
+						},
+						Arguments: []ast.Expression{
+							&ast.Ident{
+								Token: token.Token{Literal: "m"},
+								Value: "m",
+							},
+						},
+					},
+					Arg1: &ast.Ident{
+						Token: token.Token{Literal: "v"},
+						Value: "v",
+					},
+					Block: []ast.Node{
+						&ast.Literal{
+							Token: token.Token{Literal: "foo"},
+						},
+					},
+					End: &ast.EndFor{
+						Token: token.Token{Literal: "endfor"},
+					},
+				},
+			},
+		},
+		{
+			name:  "list literal iterator",
+			input: `%{for v in ["a"] }foo%{endfor}`,
+			expect: []ast.Node{
+				&ast.For{
+					Token: token.Token{Literal: "for"},
+					Iterator: &ast.ListExpression{
+						Token: token.Token{Literal: "["},
+						Elements: []ast.Expression{
+							&ast.String{
+								Token: token.Token{Literal: "a"},
+								Value: "a",
+							},
+						},
+					},
+					Arg1: &ast.Ident{
+						Token: token.Token{Literal: "v"},
+						Value: "v",
+					},
+					Block: []ast.Node{
+						&ast.Literal{
+							Token: token.Token{Literal: "foo"},
+						},
+					},
+					End: &ast.EndFor{
+						Token: token.Token{Literal: "endfor"},
+					},
+				},
+			},
+		},
 		{
 			name:    "Invalid syntax - argument is not specified",
 			input:   `%{ for in list }foo%{endfor}`,
@@ -671,6 +735,44 @@ func TestIfControl(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "nested for loop",
+			input: `%{ if v }%{ for x in list }foo%{ endfor }%{endif}`,
+			expect: []ast.Node{
+				&ast.If{
+					Token: token.Token{Literal: "if"},
+					Condition: &ast.Ident{
+						Token: token.Token{Literal: "v"},
+						Value: "v",
+					},
+					Another: []*ast.ElseIf{},
+					Consequence: []ast.Node{
+						&ast.For{
+							Token: token.Token{Literal: "for"},
+							Iterator: &ast.Ident{
+								Token: token.Token{Literal: "list"},
+								Value: "list",
+							},
+							Arg1: &ast.Ident{
+								Token: token.Token{Literal: "x"},
+								Value: "x",
+							},
+							Block: []ast.Node{
+								&ast.Literal{
+									Token: token.Token{Literal: "foo"},
+								},
+							},
+							End: &ast.EndFor{
+								Token: token.Token{Literal: "endfor"},
+							},
+						},
+					},
+					End: &ast.EndIf{
+						Token: token.Token{Literal: "endif"},
+					},
+				},
+			},
+		},
 		{
 			name:    "Invalid syntax - invalid expression",
 			input:   `%{ if v == }foo%{endif}`,
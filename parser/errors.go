@@ -60,3 +60,15 @@ func UndefinedControlParserState(t token.Token) *ParseError {
 		Message: fmt.Sprintf(`Undefined control parser for "%s"`, t.Type),
 	}
 }
+
+// Lexer reports illegal token with its reason like "Unterminated string" as literal
+func IllegalToken(t token.Token) *ParseError {
+	message := "Illegal token found"
+	if t.Literal != "" {
+		message += ": " + t.Literal
+	}
+	return &ParseError{
+		Token:   t,
+		Message: message,
+	}
+}
@@ -9,6 +9,9 @@ import (
 func (p *Parser) parseExpression(precedence int) (ast.Expression, error) {
 	prefix, ok := p.prefixParsers[p.curToken.Type]
 	if !ok {
+		if p.curTokenIs(token.ILLEGAL) {
+			return nil, IllegalToken(p.curToken)
+		}
 		return nil, UndefinedPrefix(p.curToken)
 	}
 
@@ -85,3 +88,221 @@ func (p *Parser) parseInfixExpression(left ast.Expression) (ast.Expression, erro
 
 	return node, nil
 }
+
+func (p *Parser) parseConditionalExpression(condition ast.Expression) (ast.Expression, error) {
+	node := &ast.ConditionalExpression{
+		Token:     p.curToken, // point to "?" token
+		Condition: condition,
+	}
+
+	p.NextToken() // point to consequence expression start
+	consequence, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	node.Consequence = consequence
+
+	if !p.peekTokenIs(token.COLON) {
+		return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.COLON))
+	}
+	p.NextToken() // point to COLON
+	p.NextToken() // point to alternative expression start
+
+	// Parse alternative with pipeline precedence so that nested conditional is right-associative
+	// like "a ? b : c ? d : e" is treated as "a ? b : (c ? d : e)",
+	// and following pipeline is applied to whole conditional like "(a ? b : c) | f"
+	alternative, err := p.parseExpression(PIPELINE)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	node.Alternative = alternative
+
+	return node, nil
+}
+
+func (p *Parser) parseCallExpression(left ast.Expression) (ast.Expression, error) {
+	// Function name must be an identifier
+	ident, ok := left.(*ast.Ident)
+	if !ok {
+		return nil, errors.WithStack(UnexpectedToken(p.curToken))
+	}
+
+	args, err := p.parseCallArguments()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &ast.CallExpression{
+		Token:     ident.Token, // point to function name token
+		Function:  ident,
+		Arguments: args,
+	}, nil
+}
+
+// Parse comma-separated arguments, current token must point to LEFT_PAREN
+func (p *Parser) parseCallArguments() ([]ast.Expression, error) {
+	args := []ast.Expression{}
+
+	for !p.peekTokenIs(token.RIGHT_PAREN) {
+		p.NextToken() // point to argument expression start
+		arg, err := p.parseExpression(LOWEST)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		args = append(args, arg)
+
+		if p.peekTokenIs(token.RIGHT_PAREN) {
+			break
+		}
+		if !p.peekTokenIs(token.COMMA) {
+			return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.COMMA, token.RIGHT_PAREN))
+		}
+		p.NextToken() // point to COMMA
+	}
+	p.NextToken() // point to RIGHT_PAREN
+
+	return args, nil
+}
+
+// Pipeline expression like "value | fn(arg)" is desugared to the function call "fn(value, arg)".
+// Pipeline has the lowest precedence, so the left expression is applied to the filter as a whole,
+// and chained filters are applied from left to right.
+func (p *Parser) parsePipelineExpression(left ast.Expression) (ast.Expression, error) {
+	if !p.peekTokenIs(token.IDENT) {
+		return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.IDENT))
+	}
+	p.NextToken() // point to filter function name
+
+	node := &ast.CallExpression{
+		Token:     p.curToken, // point to filter function name token so that error points to the stage
+		Function:  p.parseIdent(),
+		Arguments: []ast.Expression{left},
+	}
+
+	if p.peekTokenIs(token.LEFT_PAREN) {
+		p.NextToken() // point to LEFT_PAREN
+		args, err := p.parseCallArguments()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		node.Arguments = append(node.Arguments, args...)
+	}
+
+	return node, nil
+}
+
+// Index expression like "list[i]", index could be any expression
+func (p *Parser) parseIndexExpression(left ast.Expression) (ast.Expression, error) {
+	if p.peekTokenIs(token.ASTERISK) {
+		return p.parseSplatExpression(left, false)
+	}
+	return p.parseIndex(left)
+}
+
+func (p *Parser) parseIndex(left ast.Expression) (*ast.IndexExpression, error) {
+	node := &ast.IndexExpression{
+		Token: p.curToken, // point to "[" token
+		Left:  left,
+	}
+
+	p.NextToken() // point to index expression start
+	index, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	node.Index = index
+
+	if !p.peekTokenIs(token.RIGHT_BRACKET) {
+		return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.RIGHT_BRACKET))
+	}
+	p.NextToken() // point to RIGHT_BRACKET
+
+	return node, nil
+}
+
+// Attribute access like "list[i].name" is treated as index expression with string index
+func (p *Parser) parseAttributeExpression(left ast.Expression) (ast.Expression, error) {
+	if p.peekTokenIs(token.ASTERISK) {
+		return p.parseSplatExpression(left, true)
+	}
+	return p.parseAttribute(left)
+}
+
+// Splat like "list[*].name" or "list.*.name" takes following accessors as Each expression.
+// Full splat takes all of attribute, index and optional accessors,
+// but legacy splat takes only attribute accessors as Terraform does
+func (p *Parser) parseSplatExpression(left ast.Expression, legacy bool) (ast.Expression, error) {
+	node := &ast.SplatExpression{
+		Token:  p.curToken, // point to "[" or "." token
+		Left:   left,
+		Legacy: legacy,
+	}
+	p.NextToken() // point to ASTERISK
+
+	if !legacy {
+		if !p.peekTokenIs(token.RIGHT_BRACKET) {
+			return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.RIGHT_BRACKET))
+		}
+		p.NextToken() // point to RIGHT_BRACKET
+	}
+
+	var each ast.Expression = &ast.SplatElement{Token: node.Token}
+	for {
+		var err error
+		switch {
+		case p.peekTokenIs(token.DOT):
+			p.NextToken() // point to DOT
+			each, err = p.parseAttributeExpression(each)
+		case !legacy && p.peekTokenIs(token.LEFT_BRACKET):
+			p.NextToken() // point to LEFT_BRACKET
+			each, err = p.parseIndexExpression(each)
+		case !legacy && p.peekTokenIs(token.OPTIONAL):
+			p.NextToken() // point to OPTIONAL
+			each, err = p.parseOptionalExpression(each)
+		default:
+			node.Each = each
+			return node, nil
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+}
+
+// Optional chaining like "list[i]?.name" or "list[i]?.[0]" yields null instead of an error
+// when the left value is null or the data is missing
+func (p *Parser) parseOptionalExpression(left ast.Expression) (ast.Expression, error) {
+	var node *ast.IndexExpression
+	var err error
+
+	switch {
+	case p.peekTokenIs(token.IDENT):
+		node, err = p.parseAttribute(left)
+	case p.peekTokenIs(token.LEFT_BRACKET):
+		p.NextToken() // point to LEFT_BRACKET
+		node, err = p.parseIndex(left)
+	default:
+		return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.IDENT, token.LEFT_BRACKET))
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	node.Optional = true
+
+	return node, nil
+}
+
+func (p *Parser) parseAttribute(left ast.Expression) (*ast.IndexExpression, error) {
+	node := &ast.IndexExpression{
+		Token: p.curToken, // point to "." token
+		Left:  left,
+	}
+
+	if !p.peekTokenIs(token.IDENT) {
+		return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.IDENT))
+	}
+	p.NextToken() // point to attribute name
+	node.Index = p.parseString()
+
+	return node, nil
+}
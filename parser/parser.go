@@ -11,10 +11,15 @@ import (
 
 const (
 	LOWEST int = iota + 1
+	PIPELINE
+	CONDITIONAL
+	COALESCE
 	OR
 	AND
 	EQUALS
 	LESS_GREATER
+	SUM
+	PRODUCT
 	PREFIX
 	GROUP
 	END
@@ -27,12 +32,23 @@ var precedences = map[token.TokenType]int{
 	token.GREATER_THAN_EQUAL: LESS_GREATER,
 	token.LESS_THAN:          LESS_GREATER,
 	token.LESS_THAN_EQUAL:    LESS_GREATER,
+	token.PLUS:               SUM,
+	token.MINUS:              SUM,
+	token.ASTERISK:           PRODUCT,
+	token.SLASH:              PRODUCT,
+	token.PERCENT:            PRODUCT,
 	token.STRING:             PREFIX,
 	token.IDENT:              PREFIX,
 	token.IF:                 PREFIX,
 	token.LEFT_PAREN:         GROUP,
+	token.LEFT_BRACKET:       GROUP,
+	token.DOT:                GROUP,
+	token.OPTIONAL:           GROUP,
+	token.COALESCE:           COALESCE,
 	token.AND:                AND,
 	token.OR:                 OR,
+	token.QUESTION:           CONDITIONAL,
+	token.PIPE:               PIPELINE,
 }
 
 type (
@@ -67,15 +83,20 @@ func New(l *lexer.Lexer) *Parser {
 		l: l,
 	}
 	p.prefixParsers = map[token.TokenType]prefixParser{
-		token.IDENT:      func() (ast.Expression, error) { return p.parseIdent(), nil },
-		token.STRING:     func() (ast.Expression, error) { return p.parseString(), nil },
-		token.INT:        func() (ast.Expression, error) { return p.parseInt() },
-		token.FLOAT:      func() (ast.Expression, error) { return p.parseFloat() },
-		token.NOT:        func() (ast.Expression, error) { return p.parsePrefixExpression() },
-		token.MINUS:      func() (ast.Expression, error) { return p.parsePrefixExpression() },
-		token.TRUE:       func() (ast.Expression, error) { return p.parseBool(), nil },
-		token.FALSE:      func() (ast.Expression, error) { return p.parseBool(), nil },
-		token.LEFT_PAREN: func() (ast.Expression, error) { return p.parseGroupedExpression() },
+		token.IDENT:          func() (ast.Expression, error) { return p.parseIdent(), nil },
+		token.STRING:         func() (ast.Expression, error) { return p.parseString(), nil },
+		token.INT:            func() (ast.Expression, error) { return p.parseInt() },
+		token.FLOAT:          func() (ast.Expression, error) { return p.parseFloat() },
+		token.NOT:            func() (ast.Expression, error) { return p.parsePrefixExpression() },
+		token.MINUS:          func() (ast.Expression, error) { return p.parsePrefixExpression() },
+		token.TRUE:           func() (ast.Expression, error) { return p.parseBool(), nil },
+		token.FALSE:          func() (ast.Expression, error) { return p.parseBool(), nil },
+		token.NULL:           func() (ast.Expression, error) { return p.parseNull(), nil },
+		token.LEFT_PAREN:     func() (ast.Expression, error) { return p.parseGroupedExpression() },
+		token.LEFT_BRACKET:   func() (ast.Expression, error) { return p.parseListExpression() },
+		token.LEFT_BRACE:     func() (ast.Expression, error) { return p.parseObjectExpression() },
+		token.TEMPLATE_START: func() (ast.Expression, error) { return p.parseTemplateExpression() },
+		token.HEREDOC:        func() (ast.Expression, error) { return p.parseTemplateExpression() },
 	}
 	p.infixParsers = map[token.TokenType]infixParser{
 		token.EQUAL:              p.parseInfixExpression,
@@ -86,6 +107,18 @@ func New(l *lexer.Lexer) *Parser {
 		token.LESS_THAN_EQUAL:    p.parseInfixExpression,
 		token.AND:                p.parseInfixExpression,
 		token.OR:                 p.parseInfixExpression,
+		token.PLUS:               p.parseInfixExpression,
+		token.MINUS:              p.parseInfixExpression,
+		token.ASTERISK:           p.parseInfixExpression,
+		token.SLASH:              p.parseInfixExpression,
+		token.PERCENT:            p.parseInfixExpression,
+		token.QUESTION:           p.parseConditionalExpression,
+		token.LEFT_PAREN:         p.parseCallExpression,
+		token.PIPE:               p.parsePipelineExpression,
+		token.LEFT_BRACKET:       p.parseIndexExpression,
+		token.DOT:                p.parseAttributeExpression,
+		token.OPTIONAL:           p.parseOptionalExpression,
+		token.COALESCE:           p.parseInfixExpression,
 	}
 	p.controlParsers = map[controlState]map[token.TokenType]controlParser{
 		ROOT: {
@@ -186,11 +219,30 @@ func (p *Parser) parse() (ast.Node, error) {
 	case token.CONTROL_START:
 		return p.parseControl(ROOT)
 	case token.INTERPORATION:
-		return &ast.Interporation{
-			Token: p.curToken,
-			Value: p.parseIdent(),
-		}, nil
+		return p.parseInterporation()
 	default:
 		return nil, errors.WithStack(UnexpectedToken(p.curToken))
 	}
 }
+
+func (p *Parser) parseInterporation() (*ast.Interporation, error) {
+	node := &ast.Interporation{
+		Token: p.curToken,
+	}
+
+	p.NextToken() // point to expression start
+
+	exp, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	node.Value = exp
+
+	if !p.peekTokenIs(token.CONTROL_END) {
+		return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.CONTROL_END))
+	}
+	p.NextToken() // point to CONTROL_END
+	node.Token.RightTrim = p.curToken.RightTrim
+
+	return node, nil
+}
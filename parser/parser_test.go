@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -77,7 +79,7 @@ That's all, very simplified!
 				},
 				&ast.Interporation{
 					Token: token.Token{
-						Literal: "v",
+						Literal: "${",
 					},
 					Value: &ast.Ident{
 						Token: token.Token{
@@ -177,7 +179,7 @@ That's all, very simplified!
 					Token: token.Token{Literal: "\nif expression is also supported. Interporation is "},
 				},
 				&ast.Interporation{
-					Token: token.Token{Literal: "v"},
+					Token: token.Token{Literal: "${"},
 					Value: &ast.Ident{
 						Token: token.Token{Literal: "v"},
 						Value: "v",
@@ -277,6 +279,1044 @@ That's all, very simplified!
 	}
 }
 
+func TestInterporation(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		expect  []ast.Node
+		isError bool
+	}{
+		{
+			name:  "identifier",
+			input: "${v}",
+			expect: []ast.Node{
+				&ast.Interporation{
+					Token: token.Token{Literal: "${"},
+					Value: &ast.Ident{
+						Token: token.Token{Literal: "v"},
+						Value: "v",
+					},
+				},
+			},
+		},
+		{
+			name:  "expression with trimming",
+			input: `${~ v != "foo" ~}`,
+			expect: []ast.Node{
+				&ast.Interporation{
+					Token: token.Token{Literal: "${~", LeftTrim: true, RightTrim: true},
+					Value: &ast.InfixExpression{
+						Token: token.Token{Literal: "!="},
+						Left: &ast.Ident{
+							Token: token.Token{Literal: "v"},
+							Value: "v",
+						},
+						Operator: "!=",
+						Right: &ast.String{
+							Token: token.Token{Literal: "foo"},
+							Value: "foo",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "null literal",
+			input: "${v == null}",
+			expect: []ast.Node{
+				&ast.Interporation{
+					Token: token.Token{Literal: "${"},
+					Value: &ast.InfixExpression{
+						Token: token.Token{Literal: "=="},
+						Left: &ast.Ident{
+							Token: token.Token{Literal: "v"},
+							Value: "v",
+						},
+						Operator: "==",
+						Right: &ast.Null{
+							Token: token.Token{Literal: "null"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "Invalid syntax - empty interporation",
+			input:   "${}",
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - interporation is not closed",
+			input:   "${v w}",
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := New(lexer.NewFromString(tt.input)).Parse()
+			if err != nil {
+				if !tt.isError {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if tt.isError {
+				t.Errorf("Expects error but got nil")
+				return
+			}
+			if diff := cmp.Diff(tt.expect, parsed, ignores...); diff != "" {
+				t.Errorf("Unmatch parsed result, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestArithmeticPrecedence(t *testing.T) {
+	ident := func(name string) *ast.Ident {
+		return &ast.Ident{Token: token.Token{Literal: name}, Value: name}
+	}
+	infix := func(left ast.Expression, op string, right ast.Expression) *ast.InfixExpression {
+		return &ast.InfixExpression{Token: token.Token{Literal: op}, Left: left, Operator: op, Right: right}
+	}
+
+	tests := []struct {
+		input  string
+		expect ast.Expression
+	}{
+		{
+			input:  "${a + b * c}",
+			expect: infix(ident("a"), "+", infix(ident("b"), "*", ident("c"))),
+		},
+		{
+			input:  "${a - b - c}",
+			expect: infix(infix(ident("a"), "-", ident("b")), "-", ident("c")),
+		},
+		{
+			input:  "${a % b == c / d}",
+			expect: infix(infix(ident("a"), "%", ident("b")), "==", infix(ident("c"), "/", ident("d"))),
+		},
+		{
+			input:  "${a + b > c && d}",
+			expect: infix(infix(infix(ident("a"), "+", ident("b")), ">", ident("c")), "&&", ident("d")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			parsed, err := New(lexer.NewFromString(tt.input)).Parse()
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			expect := []ast.Node{
+				&ast.Interporation{Token: token.Token{Literal: "${"}, Value: tt.expect},
+			}
+			if diff := cmp.Diff(expect, parsed, ignores...); diff != "" {
+				t.Errorf("Unmatch parsed result, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestConditionalExpression(t *testing.T) {
+	ident := func(name string) *ast.Ident {
+		return &ast.Ident{Token: token.Token{Literal: name}, Value: name}
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  ast.Expression
+		isError bool
+	}{
+		{
+			name:  "basic conditional",
+			input: "${a == b ? c : d}",
+			expect: &ast.ConditionalExpression{
+				Token: token.Token{Literal: "?"},
+				Condition: &ast.InfixExpression{
+					Token:    token.Token{Literal: "=="},
+					Left:     ident("a"),
+					Operator: "==",
+					Right:    ident("b"),
+				},
+				Consequence: ident("c"),
+				Alternative: ident("d"),
+			},
+		},
+		{
+			name:  "right associative",
+			input: "${a ? b : c ? d : e}",
+			expect: &ast.ConditionalExpression{
+				Token:       token.Token{Literal: "?"},
+				Condition:   ident("a"),
+				Consequence: ident("b"),
+				Alternative: &ast.ConditionalExpression{
+					Token:       token.Token{Literal: "?"},
+					Condition:   ident("c"),
+					Consequence: ident("d"),
+					Alternative: ident("e"),
+				},
+			},
+		},
+		{
+			name:  "lower than logical operator",
+			input: "${a || b ? c : d}",
+			expect: &ast.ConditionalExpression{
+				Token: token.Token{Literal: "?"},
+				Condition: &ast.InfixExpression{
+					Token:    token.Token{Literal: "||"},
+					Left:     ident("a"),
+					Operator: "||",
+					Right:    ident("b"),
+				},
+				Consequence: ident("c"),
+				Alternative: ident("d"),
+			},
+		},
+		{
+			name:    "Invalid syntax - colon is not specified",
+			input:   "${a ? b}",
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := New(lexer.NewFromString(tt.input)).Parse()
+			if err != nil {
+				if !tt.isError {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if tt.isError {
+				t.Errorf("Expects error but got nil")
+				return
+			}
+			expect := []ast.Node{
+				&ast.Interporation{Token: token.Token{Literal: "${"}, Value: tt.expect},
+			}
+			if diff := cmp.Diff(expect, parsed, ignores...); diff != "" {
+				t.Errorf("Unmatch parsed result, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestCallExpression(t *testing.T) {
+	ident := func(name string) *ast.Ident {
+		return &ast.Ident{Token: token.Token{Literal: name}, Value: name}
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  ast.Expression
+		isError bool
+	}{
+		{
+			name:  "no arguments",
+			input: "${fn()}",
+			expect: &ast.CallExpression{
+				Token:     token.Token{Literal: "fn"},
+				Function:  ident("fn"),
+				Arguments: []ast.Expression{},
+			},
+		},
+		{
+			name:  "multiple arguments",
+			input: `${fn(a, "b", 1 + 2)}`,
+			expect: &ast.CallExpression{
+				Token:    token.Token{Literal: "fn"},
+				Function: ident("fn"),
+				Arguments: []ast.Expression{
+					ident("a"),
+					&ast.String{Token: token.Token{Literal: "b"}, Value: "b"},
+					&ast.InfixExpression{
+						Token:    token.Token{Literal: "+"},
+						Left:     &ast.Int{Token: token.Token{Literal: "1"}, Value: 1},
+						Operator: "+",
+						Right:    &ast.Int{Token: token.Token{Literal: "2"}, Value: 2},
+					},
+				},
+			},
+		},
+		{
+			name:  "nested call with prefix",
+			input: "${!fn(inner(a))}",
+			expect: &ast.PrefixExpression{
+				Token:    token.Token{Literal: "!"},
+				Operator: "!",
+				Right: &ast.CallExpression{
+					Token:    token.Token{Literal: "fn"},
+					Function: ident("fn"),
+					Arguments: []ast.Expression{
+						&ast.CallExpression{
+							Token:     token.Token{Literal: "inner"},
+							Function:  ident("inner"),
+							Arguments: []ast.Expression{ident("a")},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "Invalid syntax - not closed",
+			input:   "${fn(a}",
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - missing comma",
+			input:   "${fn(a b)}",
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - calling not identifier",
+			input:   `${"fn"(a)}`,
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := New(lexer.NewFromString(tt.input)).Parse()
+			if err != nil {
+				if !tt.isError {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if tt.isError {
+				t.Errorf("Expects error but got nil")
+				return
+			}
+			expect := []ast.Node{
+				&ast.Interporation{Token: token.Token{Literal: "${"}, Value: tt.expect},
+			}
+			if diff := cmp.Diff(expect, parsed, ignores...); diff != "" {
+				t.Errorf("Unmatch parsed result, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestPipelineExpression(t *testing.T) {
+	ident := func(name string) *ast.Ident {
+		return &ast.Ident{Token: token.Token{Literal: name}, Value: name}
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  ast.Expression
+		isError bool
+	}{
+		{
+			name:  "single filter",
+			input: "${v | upper}",
+			expect: &ast.CallExpression{
+				Token:     token.Token{Literal: "upper"},
+				Function:  ident("upper"),
+				Arguments: []ast.Expression{ident("v")},
+			},
+		},
+		{
+			name:  "chained filters with arguments",
+			input: "${v | upper | truncate(20, w)}",
+			expect: &ast.CallExpression{
+				Token:    token.Token{Literal: "truncate"},
+				Function: ident("truncate"),
+				Arguments: []ast.Expression{
+					&ast.CallExpression{
+						Token:     token.Token{Literal: "upper"},
+						Function:  ident("upper"),
+						Arguments: []ast.Expression{ident("v")},
+					},
+					&ast.Int{Token: token.Token{Literal: "20"}, Value: 20},
+					ident("w"),
+				},
+			},
+		},
+		{
+			name:  "filter is applied to whole OR expression",
+			input: "${a || b | f}",
+			expect: &ast.CallExpression{
+				Token:    token.Token{Literal: "f"},
+				Function: ident("f"),
+				Arguments: []ast.Expression{
+					&ast.InfixExpression{
+						Token:    token.Token{Literal: "||"},
+						Left:     ident("a"),
+						Operator: "||",
+						Right:    ident("b"),
+					},
+				},
+			},
+		},
+		{
+			name:  "filter is applied to whole conditional expression",
+			input: "${a ? b : c | f}",
+			expect: &ast.CallExpression{
+				Token:    token.Token{Literal: "f"},
+				Function: ident("f"),
+				Arguments: []ast.Expression{
+					&ast.ConditionalExpression{
+						Token:       token.Token{Literal: "?"},
+						Condition:   ident("a"),
+						Consequence: ident("b"),
+						Alternative: ident("c"),
+					},
+				},
+			},
+		},
+		{
+			name:  "filter in grouped expression",
+			input: "${(a | f) + b}",
+			expect: &ast.InfixExpression{
+				Token: token.Token{Literal: "+"},
+				Left: &ast.GroupedExpression{
+					Token: token.Token{Literal: "("},
+					Right: &ast.CallExpression{
+						Token:     token.Token{Literal: "f"},
+						Function:  ident("f"),
+						Arguments: []ast.Expression{ident("a")},
+					},
+				},
+				Operator: "+",
+				Right:    ident("b"),
+			},
+		},
+		{
+			name:    "Invalid syntax - filter is not identifier",
+			input:   `${v | "upper"}`,
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - missing filter",
+			input:   "${v | }",
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := New(lexer.NewFromString(tt.input)).Parse()
+			if err != nil {
+				if !tt.isError {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if tt.isError {
+				t.Errorf("Expects error but got nil")
+				return
+			}
+			expect := []ast.Node{
+				&ast.Interporation{Token: token.Token{Literal: "${"}, Value: tt.expect},
+			}
+			if diff := cmp.Diff(expect, parsed, ignores...); diff != "" {
+				t.Errorf("Unmatch parsed result, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestCollectionExpression(t *testing.T) {
+	ident := func(name string) *ast.Ident {
+		return &ast.Ident{Token: token.Token{Literal: name}, Value: name}
+	}
+	str := func(v string) *ast.String {
+		return &ast.String{Token: token.Token{Literal: v}, Value: v}
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  ast.Expression
+		isError bool
+	}{
+		{
+			name:  "empty list",
+			input: "${[]}",
+			expect: &ast.ListExpression{
+				Token:    token.Token{Literal: "["},
+				Elements: []ast.Expression{},
+			},
+		},
+		{
+			name:  "list with trailing comma",
+			input: `${["a", b,]}`,
+			expect: &ast.ListExpression{
+				Token:    token.Token{Literal: "["},
+				Elements: []ast.Expression{str("a"), ident("b")},
+			},
+		},
+		{
+			name:  "multi-line list",
+			input: "${[\n  \"a\",\n  b\n]}",
+			expect: &ast.ListExpression{
+				Token:    token.Token{Literal: "["},
+				Elements: []ast.Expression{str("a"), ident("b")},
+			},
+		},
+		{
+			name:  "object with mixed separators",
+			input: `${{ a = 1, "b": c }}`,
+			expect: &ast.ObjectExpression{
+				Token: token.Token{Literal: "{"},
+				Items: []*ast.ObjectItem{
+					{Key: ident("a"), Value: &ast.Int{Token: token.Token{Literal: "1"}, Value: 1}},
+					{Key: str("b"), Value: ident("c")},
+				},
+			},
+		},
+		{
+			name:  "multi-line object with expression key",
+			input: "${{\n  (k) = [v]\n  c = {}\n}}",
+			expect: &ast.ObjectExpression{
+				Token: token.Token{Literal: "{"},
+				Items: []*ast.ObjectItem{
+					{
+						Key: &ast.GroupedExpression{Token: token.Token{Literal: "("}, Right: ident("k")},
+						Value: &ast.ListExpression{
+							Token:    token.Token{Literal: "["},
+							Elements: []ast.Expression{ident("v")},
+						},
+					},
+					{
+						Key:   ident("c"),
+						Value: &ast.ObjectExpression{Token: token.Token{Literal: "{"}, Items: []*ast.ObjectItem{}},
+					},
+				},
+			},
+		},
+		{
+			name:    "Invalid syntax - missing comma in list",
+			input:   `${["a" "b"]}`,
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - line feed separated list",
+			input:   "${[\"a\"\n\"b\"]}",
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - missing object value",
+			input:   `${{ a = }}`,
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - number object key",
+			input:   `${{ 1 = 2 }}`,
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := New(lexer.NewFromString(tt.input)).Parse()
+			if err != nil {
+				if !tt.isError {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if tt.isError {
+				t.Errorf("Expects error but got nil")
+				return
+			}
+			expect := []ast.Node{
+				&ast.Interporation{Token: token.Token{Literal: "${"}, Value: tt.expect},
+			}
+			if diff := cmp.Diff(expect, parsed, ignores...); diff != "" {
+				t.Errorf("Unmatch parsed result, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestForExpression(t *testing.T) {
+	ident := func(name string) *ast.Ident {
+		return &ast.Ident{Token: token.Token{Literal: name}, Value: name}
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  ast.Expression
+		isError bool
+	}{
+		{
+			name:  "list result with condition",
+			input: `${[for s in list : upper(s) if s != ""]}`,
+			expect: &ast.ForExpression{
+				Token:      token.Token{Literal: "["},
+				Arg1:       ident("s"),
+				Collection: ident("list"),
+				Value: &ast.CallExpression{
+					Token:     token.Token{Literal: "upper"},
+					Function:  ident("upper"),
+					Arguments: []ast.Expression{ident("s")},
+				},
+				Condition: &ast.InfixExpression{
+					Token:    token.Token{Literal: "!="},
+					Left:     ident("s"),
+					Operator: "!=",
+					Right:    &ast.String{Token: token.Token{Literal: ""}, Value: ""},
+				},
+			},
+		},
+		{
+			name:  "object result",
+			input: `${{for k, v in m : k => v}}`,
+			expect: &ast.ForExpression{
+				Token:      token.Token{Literal: "{"},
+				Arg1:       ident("k"),
+				Arg2:       ident("v"),
+				Collection: ident("m"),
+				Key:        ident("k"),
+				Value:      ident("v"),
+			},
+		},
+		{
+			name:  "object result with grouping in multiple lines",
+			input: "${{\n  for v in list\n  : v => v...\n  if v\n}}",
+			expect: &ast.ForExpression{
+				Token:      token.Token{Literal: "{"},
+				Arg1:       ident("v"),
+				Collection: ident("list"),
+				Key:        ident("v"),
+				Value:      ident("v"),
+				Condition:  ident("v"),
+				Grouping:   true,
+			},
+		},
+		{
+			name:    "Invalid syntax - missing colon",
+			input:   `${[for s in list upper(s)]}`,
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - missing arrow in object result",
+			input:   `${{for k, v in m : v}}`,
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - arrow in list result",
+			input:   `${[for k, v in m : k => v]}`,
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - grouping in list result",
+			input:   `${[for v in list : v...]}`,
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := New(lexer.NewFromString(tt.input)).Parse()
+			if err != nil {
+				if !tt.isError {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if tt.isError {
+				t.Errorf("Expects error but got nil")
+				return
+			}
+			expect := []ast.Node{
+				&ast.Interporation{Token: token.Token{Literal: "${"}, Value: tt.expect},
+			}
+			if diff := cmp.Diff(expect, parsed, ignores...); diff != "" {
+				t.Errorf("Unmatch parsed result, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestIndexExpression(t *testing.T) {
+	ident := func(name string) *ast.Ident {
+		return &ast.Ident{Token: token.Token{Literal: name}, Value: name}
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  ast.Expression
+		isError bool
+	}{
+		{
+			name:  "variable index",
+			input: "${regions[name]}",
+			expect: &ast.IndexExpression{
+				Token: token.Token{Literal: "["},
+				Left:  ident("regions"),
+				Index: ident("name"),
+			},
+		},
+		{
+			name:   "static index is a part of identifier",
+			input:  `${list[0]}`,
+			expect: ident("list[0]"),
+		},
+		{
+			name:  "chained index and attribute",
+			input: "${list[i + 1].name}",
+			expect: &ast.IndexExpression{
+				Token: token.Token{Literal: "."},
+				Left: &ast.IndexExpression{
+					Token: token.Token{Literal: "["},
+					Left:  ident("list"),
+					Index: &ast.InfixExpression{
+						Token:    token.Token{Literal: "+"},
+						Left:     ident("i"),
+						Operator: "+",
+						Right:    &ast.Int{Token: token.Token{Literal: "1"}, Value: 1},
+					},
+				},
+				Index: &ast.String{Token: token.Token{Literal: "name"}, Value: "name"},
+			},
+		},
+		{
+			name:  "index has higher precedence than prefix",
+			input: "${-list[i]}",
+			expect: &ast.PrefixExpression{
+				Token:    token.Token{Literal: "-"},
+				Operator: "-",
+				Right: &ast.IndexExpression{
+					Token: token.Token{Literal: "["},
+					Left:  ident("list"),
+					Index: ident("i"),
+				},
+			},
+		},
+		{
+			name:  "index for function call",
+			input: "${keys(m)[0]}",
+			expect: &ast.IndexExpression{
+				Token: token.Token{Literal: "["},
+				Left: &ast.CallExpression{
+					Token:     token.Token{Literal: "keys"},
+					Function:  ident("keys"),
+					Arguments: []ast.Expression{ident("m")},
+				},
+				Index: &ast.Int{Token: token.Token{Literal: "0"}, Value: 0},
+			},
+		},
+		{
+			name:  "optional attribute and index",
+			input: "${list[i]?.name?.[0]}",
+			expect: &ast.IndexExpression{
+				Token: token.Token{Literal: "["},
+				Left: &ast.IndexExpression{
+					Token:    token.Token{Literal: "?."},
+					Left:     &ast.IndexExpression{Token: token.Token{Literal: "["}, Left: ident("list"), Index: ident("i")},
+					Index:    &ast.String{Token: token.Token{Literal: "name"}, Value: "name"},
+					Optional: true,
+				},
+				Index:    &ast.Int{Token: token.Token{Literal: "0"}, Value: 0},
+				Optional: true,
+			},
+		},
+		{
+			name:  "coalesce has lower precedence than logical operators",
+			input: `${a || b ?? c == d}`,
+			expect: &ast.InfixExpression{
+				Token: token.Token{Literal: "??"},
+				Left: &ast.InfixExpression{
+					Token:    token.Token{Literal: "||"},
+					Left:     ident("a"),
+					Operator: "||",
+					Right:    ident("b"),
+				},
+				Operator: "??",
+				Right: &ast.InfixExpression{
+					Token:    token.Token{Literal: "=="},
+					Left:     ident("c"),
+					Operator: "==",
+					Right:    ident("d"),
+				},
+			},
+		},
+		{
+			name:  "coalesce has higher precedence than conditional",
+			input: `${a ?? b ? c : d}`,
+			expect: &ast.ConditionalExpression{
+				Token: token.Token{Literal: "?"},
+				Condition: &ast.InfixExpression{
+					Token:    token.Token{Literal: "??"},
+					Left:     ident("a"),
+					Operator: "??",
+					Right:    ident("b"),
+				},
+				Consequence: ident("c"),
+				Alternative: ident("d"),
+			},
+		},
+		{
+			name:  "full splat after dynamic index",
+			input: "${servers[i].Tags[*]}",
+			expect: &ast.SplatExpression{
+				Token: token.Token{Literal: "["},
+				Left: &ast.IndexExpression{
+					Token: token.Token{Literal: "."},
+					Left:  &ast.IndexExpression{Token: token.Token{Literal: "["}, Left: ident("servers"), Index: ident("i")},
+					Index: &ast.String{Token: token.Token{Literal: "Tags"}, Value: "Tags"},
+				},
+				Each: &ast.SplatElement{Token: token.Token{Literal: "["}},
+			},
+		},
+		{
+			name:  "full splat applies following attribute and index to each element",
+			input: "${values(m)[*].Tags[i]}",
+			expect: &ast.SplatExpression{
+				Token: token.Token{Literal: "["},
+				Left: &ast.CallExpression{
+					Token:     token.Token{Literal: "values"},
+					Function:  ident("values"),
+					Arguments: []ast.Expression{ident("m")},
+				},
+				Each: &ast.IndexExpression{
+					Token: token.Token{Literal: "["},
+					Left: &ast.IndexExpression{
+						Token: token.Token{Literal: "."},
+						Left:  &ast.SplatElement{Token: token.Token{Literal: "["}},
+						Index: &ast.String{Token: token.Token{Literal: "Tags"}, Value: "Tags"},
+					},
+					Index: ident("i"),
+				},
+			},
+		},
+		{
+			name:  "legacy splat applies only attributes to each element",
+			input: "${groups[k].*.Name[0]}",
+			expect: &ast.IndexExpression{
+				Token: token.Token{Literal: "["},
+				Left: &ast.SplatExpression{
+					Token: token.Token{Literal: "."},
+					Left:  &ast.IndexExpression{Token: token.Token{Literal: "["}, Left: ident("groups"), Index: ident("k")},
+					Each: &ast.IndexExpression{
+						Token: token.Token{Literal: "."},
+						Left:  &ast.SplatElement{Token: token.Token{Literal: "."}},
+						Index: &ast.String{Token: token.Token{Literal: "Name"}, Value: "Name"},
+					},
+					Legacy: true,
+				},
+				Index: &ast.Int{Token: token.Token{Literal: "0"}, Value: 0},
+			},
+		},
+		{
+			name:    "Invalid syntax - splat not closed",
+			input:   "${list[*}",
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - not closed",
+			input:   "${list[i}",
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - optional chaining without attribute",
+			input:   "${list[i]?.}",
+			isError: true,
+		},
+		{
+			name:    "Invalid syntax - attribute is not identifier",
+			input:   `${list[i]."name"}`,
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := New(lexer.NewFromString(tt.input)).Parse()
+			if err != nil {
+				if !tt.isError {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if tt.isError {
+				t.Errorf("Expects error but got nil")
+				return
+			}
+			expect := []ast.Node{
+				&ast.Interporation{Token: token.Token{Literal: "${"}, Value: tt.expect},
+			}
+			if diff := cmp.Diff(expect, parsed, ignores...); diff != "" {
+				t.Errorf("Unmatch parsed result, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestTemplateExpression(t *testing.T) {
+	ident := func(name string) *ast.Ident {
+		return &ast.Ident{Token: token.Token{Literal: name}, Value: name}
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  ast.Expression
+		isError bool
+	}{
+		{
+			name:   "plain string with escapes",
+			input:  `${"a\"b"}`,
+			expect: &ast.String{Token: token.Token{Literal: `a"b`}, Value: `a"b`},
+		},
+		{
+			name:  "interporation inside string",
+			input: `${"Hi ${name}!"}`,
+			expect: &ast.TemplateExpression{
+				Token: token.Token{Literal: "Hi "},
+				Parts: []ast.Node{
+					&ast.Literal{Token: token.Token{Literal: "Hi "}},
+					&ast.Interporation{Token: token.Token{Literal: "${"}, Value: ident("name")},
+					&ast.Literal{Token: token.Token{Literal: "!"}},
+				},
+			},
+		},
+		{
+			name:  "nested string template",
+			input: `${"${"x${v}"}"}`,
+			expect: &ast.TemplateExpression{
+				Token: token.Token{Literal: ""},
+				Parts: []ast.Node{
+					&ast.Interporation{
+						Token: token.Token{Literal: "${"},
+						Value: &ast.TemplateExpression{
+							Token: token.Token{Literal: "x"},
+							Parts: []ast.Node{
+								&ast.Literal{Token: token.Token{Literal: "x"}},
+								&ast.Interporation{Token: token.Token{Literal: "${"}, Value: ident("v")},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "control inside string",
+			input: `${"%{ if v }y%{ endif }"}`,
+			expect: &ast.TemplateExpression{
+				Token: token.Token{Literal: ""},
+				Parts: []ast.Node{
+					&ast.If{
+						Token:       token.Token{Literal: "if"},
+						Condition:   ident("v"),
+						Another:     []*ast.ElseIf{},
+						Consequence: []ast.Node{&ast.Literal{Token: token.Token{Literal: "y"}}},
+						End:         &ast.EndIf{Token: token.Token{Literal: "endif"}},
+					},
+				},
+			},
+		},
+		{
+			name:  "heredoc",
+			input: "${<<EOT\n  a${v}\nEOT\n}",
+			expect: &ast.TemplateExpression{
+				Token: token.Token{Literal: "<<EOT"},
+				Parts: []ast.Node{
+					&ast.Literal{Token: token.Token{Literal: "  a"}},
+					&ast.Interporation{Token: token.Token{Literal: "${"}, Value: ident("v")},
+					&ast.Literal{Token: token.Token{Literal: "\n"}},
+				},
+			},
+		},
+		{
+			name:  "indented heredoc strips common indentation",
+			input: "${<<-EOT\n    a\n\n      b${v}\n    EOT\n}",
+			expect: &ast.TemplateExpression{
+				Token: token.Token{Literal: "<<-EOT"},
+				Parts: []ast.Node{
+					&ast.Literal{Token: token.Token{Literal: "a\n\n  b"}},
+					&ast.Interporation{Token: token.Token{Literal: "${"}, Value: ident("v")},
+					&ast.Literal{Token: token.Token{Literal: "\n"}},
+				},
+			},
+		},
+		{
+			name:  "indented heredoc with line which starts with template sequence",
+			input: "${<<-EOT\n  a\n${v}\n  EOT\n}",
+			expect: &ast.TemplateExpression{
+				Token: token.Token{Literal: "<<-EOT"},
+				Parts: []ast.Node{
+					&ast.Literal{Token: token.Token{Literal: "  a\n"}},
+					&ast.Interporation{Token: token.Token{Literal: "${"}, Value: ident("v")},
+					&ast.Literal{Token: token.Token{Literal: "\n"}},
+				},
+			},
+		},
+		{
+			name:    "unterminated heredoc",
+			input:   "${<<EOT\nabc\n}",
+			isError: true,
+		},
+		{
+			name:    "unterminated string",
+			input:   `${"abc}`,
+			isError: true,
+		},
+		{
+			name:    "unterminated string template",
+			input:   `${"a${b}`,
+			isError: true,
+		},
+		{
+			name:    "invalid escape sequence",
+			input:   `${"\q"}`,
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := New(lexer.NewFromString(tt.input)).Parse()
+			if err != nil {
+				if !tt.isError {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if tt.isError {
+				t.Errorf("Expects error but got nil")
+				return
+			}
+			expect := []ast.Node{
+				&ast.Interporation{Token: token.Token{Literal: "${"}, Value: tt.expect},
+			}
+			if diff := cmp.Diff(expect, parsed, ignores...); diff != "" {
+				t.Errorf("Unmatch parsed result, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnterminatedStringPosition(t *testing.T) {
+	_, err := New(lexer.NewFromString("line1\n${ upper(\"a${v}) }")).Parse()
+	if err == nil {
+		t.Errorf("Expects error but got nil")
+		return
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Errorf("Expects ParseError, but got %T", err)
+		return
+	}
+	if diff := cmp.Diff([]int{2, 10}, []int{pe.Token.Line, pe.Token.Position}); diff != "" {
+		t.Errorf("Error position mismatch, diff=%s", diff)
+	}
+	if !strings.Contains(pe.Message, "Unterminated string") {
+		t.Errorf("Unexpected error message: %s", pe.Message)
+	}
+}
+
 func BenchmarkPar(b *testing.B) {
 	input := `This is template spec.
 
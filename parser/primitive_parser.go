@@ -52,3 +52,9 @@ func (p *Parser) parseBool() *ast.Bool {
 		Value: p.curToken.Type == token.TRUE,
 	}
 }
+
+func (p *Parser) parseNull() *ast.Null {
+	return &ast.Null{
+		Token: p.curToken,
+	}
+}
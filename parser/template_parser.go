@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender/ast"
+	"github.com/ysugimoto/tender/token"
+)
+
+// Parse quoted string template like `"Hello, ${name}!"` or heredoc like "<<EOT",
+// current token must point to TEMPLATE_START or HEREDOC.
+// Inside parts are parsed as the same as template nodes until TEMPLATE_END is found.
+func (p *Parser) parseTemplateExpression() (*ast.TemplateExpression, error) {
+	node := &ast.TemplateExpression{
+		Token: p.curToken,
+		Parts: []ast.Node{},
+	}
+
+	// TEMPLATE_START token holds the leading literal before the first template sequence
+	if p.curTokenIs(token.TEMPLATE_START) && p.curToken.Literal != "" {
+		node.Parts = append(node.Parts, &ast.Literal{Token: p.curToken})
+	}
+
+	p.NextToken() // point to the first template sequence
+	for !p.curTokenIs(token.TEMPLATE_END) {
+		switch p.curToken.Type {
+		case token.LITERAL:
+			node.Parts = append(node.Parts, &ast.Literal{Token: p.curToken})
+		case token.INTERPORATION:
+			part, err := p.parseInterporation()
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			node.Parts = append(node.Parts, part)
+		case token.CONTROL_START:
+			part, err := p.parseControl(ROOT)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			node.Parts = append(node.Parts, part)
+		case token.ILLEGAL:
+			return nil, errors.WithStack(IllegalToken(p.curToken))
+		default:
+			return nil, errors.WithStack(UnexpectedToken(p.curToken, token.TEMPLATE_END))
+		}
+		p.NextToken()
+	}
+
+	if strings.HasPrefix(node.Token.Literal, "<<-") {
+		stripHeredocIndent(node.Parts)
+	}
+
+	return node, nil
+}
+
+// Strip the common leading whitespaces of lines from indented heredoc as the same as HCL.
+// Whitespace-only lines are ignored to find the indentation,
+// and the line which starts with template sequence is treated as no indentation.
+func stripHeredocIndent(parts []ast.Node) {
+	literals := flattenLiterals(parts, nil)
+
+	indent := -1
+	eachLineStart(literals, func(s string, blank bool) string {
+		if blank {
+			return s
+		}
+		if n := len(s) - len(strings.TrimLeft(s, " \t")); indent < 0 || n < indent {
+			indent = n
+		}
+		return s
+	})
+	if indent <= 0 {
+		return
+	}
+
+	eachLineStart(literals, func(s string, blank bool) string {
+		n := len(s) - len(strings.TrimLeft(s, " \t"))
+		if n > indent {
+			n = indent
+		}
+		return s[n:]
+	})
+}
+
+// Flatten literals in the source order, template sequence like interporation or control is represented as nil
+func flattenLiterals(nodes []ast.Node, literals []*ast.Literal) []*ast.Literal {
+	for i := range nodes {
+		switch n := nodes[i].(type) {
+		case *ast.Literal:
+			literals = append(literals, n)
+		case *ast.If:
+			literals = flattenLiterals(n.Consequence, append(literals, nil))
+			for _, another := range n.Another {
+				literals = flattenLiterals(another.Consequence, append(literals, nil))
+			}
+			if n.Alternative != nil {
+				literals = flattenLiterals(n.Alternative.Consequence, append(literals, nil))
+			}
+			literals = append(literals, nil)
+		case *ast.For:
+			literals = flattenLiterals(n.Block, append(literals, nil))
+			literals = append(literals, nil)
+		default:
+			literals = append(literals, nil)
+		}
+	}
+	return literals
+}
+
+// Call the function with the segment which starts at the beginning of line, and replace it with returned string.
+// The segment is blank when it is whitespace-only line, or trailing whitespaces at the end of heredoc.
+func eachLineStart(literals []*ast.Literal, fn func(s string, blank bool) string) {
+	lineStart := true
+	for i, literal := range literals {
+		if literal == nil {
+			if lineStart { // line starts with template sequence
+				fn("", false)
+			}
+			lineStart = false
+			continue
+		}
+
+		lines := strings.Split(literal.Token.Literal, "\n")
+		for j := range lines {
+			if j > 0 {
+				lineStart = true
+			}
+			if !lineStart {
+				continue
+			}
+			// Last segment continues to the following template sequence
+			followed := j == len(lines)-1 && i+1 < len(literals)
+			blank := strings.TrimLeft(lines[j], " \t") == "" && !followed
+			lines[j] = fn(lines[j], blank)
+		}
+		literal.Token.Literal = strings.Join(lines, "\n")
+		lineStart = false
+	}
+}
@@ -2,10 +2,8 @@ package tender
 
 import (
 	"bytes"
-	"os"
 	"reflect"
 	"sort"
-	"strings"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -13,118 +11,108 @@ import (
 	"github.com/ysugimoto/tender/value"
 )
 
-// Lookup variables from current scope local variables,
-// or global assigned variables if local variable is not found.
-func (t *Template) lookupVariable(name string) (reflect.Value, error) {
-	if len(t.locals) > 0 {
-		local := t.locals[len(t.locals)-1]
-		if v, err := local.Resolve(name); err == nil {
-			return v, nil
-		}
-	}
-	return t.global.Resolve(name)
-}
-
 var pool = sync.Pool{
 	New: func() any {
 		return new(bytes.Buffer)
 	},
 }
 
-// render the template from parsed AST Nodes.
-func (t *Template) render(nodes []ast.Node) (string, error) {
-	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
-	defer pool.Put(buf)
-
-	buf.Reset()
-
+// render the template from parsed AST Nodes to the writer.
+func (c *renderContext) render(w *writer, nodes []ast.Node) error {
 	for i := range nodes {
 		switch n := nodes[i].(type) {
 		case *ast.Literal:
-			buf.WriteString(n.Token.Literal)
+			if err := w.writeLiteral(n.Token.Literal); err != nil {
+				return errors.WithStack(err)
+			}
 		case *ast.If:
 			if n.Token.LeftTrim {
-				trimRightSpaceBuffer(buf)
+				w.trimRight()
 			}
-
-			v, err := t.renderIfControl(n)
-			if err != nil {
-				return "", errors.WithStack(err)
+			if err := c.renderIfControl(w, n); err != nil {
+				return errors.WithStack(err)
 			}
-			buf.WriteString(v)
-
 			if n.End.Token.RightTrim {
-				if i+1 < len(nodes)-1 {
-					if l, ok := nodes[i+i].(*ast.Literal); ok {
-						l.Token.Literal = trimLeftSpace(l.Token.Literal)
-					}
-				}
+				w.trimNextLeft()
 			}
 		case *ast.For:
 			if n.Token.LeftTrim {
-				trimRightSpaceBuffer(buf)
+				w.trimRight()
 			}
-
-			v, err := t.renderForControl(n)
-			if err != nil {
-				return "", errors.WithStack(err)
+			if err := c.renderForControl(w, n); err != nil {
+				return errors.WithStack(err)
 			}
-			buf.WriteString(v)
-
 			if n.End.Token.RightTrim {
-				if i+1 < len(nodes)-1 {
-					if l, ok := nodes[i+i].(*ast.Literal); ok {
-						l.Token.Literal = trimLeftSpace(l.Token.Literal)
-					}
-				}
+				w.trimNextLeft()
 			}
 		case *ast.Interporation:
-			var val string
-			if isEnvironmentVariable(n.Value.Value) {
-				v, ok := os.LookupEnv(n.Value.Value)
-				if !ok {
-					return "", errors.WithStack(errors.New(
-						`environment variable "` + n.Value.Value + `" is not specified"`,
-					))
-				}
-				val = v
-			} else {
-				v, err := t.lookupVariable(n.Value.Value)
-				if err != nil {
-					return "", errors.WithStack(err)
-				}
-				val = value.ToString(v)
+			if n.Token.LeftTrim {
+				w.trimRight()
 			}
 
-			if t.enableEscape {
+			v, err := c.evaluateExpression(n.Value)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			val := value.ToString(v)
+			if c.enableEscape {
 				val = escapeHTML(val)
 			}
-			buf.WriteString(val)
+			if err := w.writeString(val); err != nil {
+				return errors.WithStack(err)
+			}
+
+			if n.Token.RightTrim {
+				w.trimNextLeft()
+			}
 		default:
-			return "", errors.New("Unexpected node found")
+			return errors.New("Unexpected node found")
 		}
 	}
 
-	return buf.String(), nil
+	return nil
 }
 
 // Render the for control syntax
-func (t *Template) renderForControl(node *ast.For) (string, error) {
-	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
-	defer pool.Put(buf)
-
-	buf.Reset()
-
-	// Check iterator variable is assigned
-	iterator, err := t.lookupVariable(node.Iterator.Value)
+func (c *renderContext) renderForControl(w *writer, node *ast.For) error {
+	iterator, err := c.evaluateExpression(node.Iterator)
 	if err != nil {
-		return "", errors.WithStack(UndefinedVariable(node.Iterator.Token, node.Iterator.Value))
+		// Check iterator variable is assigned
+		if ident, ok := node.Iterator.(*ast.Ident); ok && !isEnvironmentVariable(ident.Value) {
+			return errors.WithStack(UndefinedVariable(ident.Token, ident.Value))
+		}
+		return errors.WithStack(err)
 	}
 
 	// For loop iterator value must be a slice of map
+	ok, err := iterate(iterator, func(key, val reflect.Value) error {
+		return c.renderForIteration(w, node, key, val)
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !ok {
+		// Otherwise, raise NotIterable error
+		return errors.WithStack(NotIterable(node.Iterator.GetToken(), iteratorName(node.Iterator)))
+	}
+
+	return nil
+}
+
+// Iterate slice or map value and call the callback with key and value.
+// Map is iterated by alphabetically sorted keys, and slice key is its index.
+// Returns false if the value is not iterable
+func iterate(v reflect.Value, fn func(key, val reflect.Value) error) (bool, error) {
+	// Unwrap interface and pointer value like an element of []any
+	if v.IsValid() {
+		v = reflect.Indirect(reflect.ValueOf(v.Interface()))
+	}
+
 	switch {
-	case value.IsMap(iterator):
-		keys := iterator.MapKeys()
+	case !v.IsValid():
+		return false, nil
+	case value.IsMap(v):
+		keys := v.MapKeys()
 		// Map look key is unordered so we will sort alphabetically
 		sort.Slice(keys, func(i, j int) bool {
 			a := value.ToString(keys[i])
@@ -133,42 +121,68 @@ func (t *Template) renderForControl(node *ast.For) (string, error) {
 		})
 
 		for i := 0; i < len(keys); i++ {
-			iteration, err := t.renderForIteration(node, keys[i], iterator.MapIndex(keys[i]))
-			if err != nil {
-				return "", errors.WithStack(err)
-			}
-			if node.Token.RightTrim {
-				iteration = trimLeftSpace(iteration)
+			if err := fn(keys[i], unwrapElement(v.MapIndex(keys[i]))); err != nil {
+				return true, errors.WithStack(err)
 			}
-			if node.End.Token.LeftTrim {
-				iteration = trimRightSpace(iteration)
-			}
-			buf.WriteString(iteration)
 		}
-	case value.IsSlice(iterator):
-		for i := 0; i < iterator.Len(); i++ {
-			iteration, err := t.renderForIteration(node, reflect.ValueOf(i), iterator.Index(i))
-			if err != nil {
-				return "", errors.WithStack(err)
+	case value.IsSlice(v):
+		for i := 0; i < v.Len(); i++ {
+			if err := fn(reflect.ValueOf(i), unwrapElement(v.Index(i))); err != nil {
+				return true, errors.WithStack(err)
 			}
-			if node.Token.RightTrim {
-				iteration = trimLeftSpace(iteration)
-			}
-			if node.End.Token.LeftTrim {
-				iteration = trimRightSpace(iteration)
-			}
-			buf.WriteString(iteration)
 		}
 	default:
-		// Otherwise, raise NotIterable error
-		return "", errors.WithStack(NotIterable(node.Iterator.Token, node.Iterator.Value))
+		return false, nil
 	}
 
-	return buf.String(), nil
+	return true, nil
+}
+
+// Element of []any or map[string]any is wrapped by interface, unwrap it to be compared or calculated
+func unwrapElement(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Interface {
+		return v.Elem()
+	}
+	return v
+}
+
+// Get the name of iterator expression for error message like "servers[i].tags".
+// Returns empty string if the expression is not a variable path or function call
+func iteratorName(expr ast.Expression) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Value
+	case *ast.CallExpression:
+		return t.Function.Value + "()"
+	case *ast.IndexExpression:
+		left := iteratorName(t.Left)
+		if left == "" {
+			return ""
+		}
+		prefix := ""
+		if t.Optional {
+			prefix = "?."
+		}
+		switch index := t.Index.(type) {
+		case *ast.String:
+			// Attribute access like "foo.bar" is parsed as index expression with string index
+			if t.Token.Literal != "[" {
+				return left + t.Token.Literal + index.Value
+			}
+			return left + prefix + `["` + index.Value + `"]`
+		case *ast.Int:
+			return left + prefix + "[" + index.Token.Literal + "]"
+		default:
+			if name := iteratorName(index); name != "" {
+				return left + prefix + "[" + name + "]"
+			}
+		}
+	}
+	return ""
 }
 
 // Process the one interation for the "for" block
-func (t *Template) renderForIteration(node *ast.For, key, val reflect.Value) (string, error) {
+func (c *renderContext) renderForIteration(w *writer, node *ast.For, key, val reflect.Value) error {
 	// Assign key and value to local variable
 	local := value.Value{
 		node.Arg1.Value: key,
@@ -178,128 +192,95 @@ func (t *Template) renderForIteration(node *ast.For, key, val reflect.Value) (st
 	}
 
 	// Push current local scoped values
-	t.locals = append(t.locals, local)
+	c.locals = append(c.locals, local)
 	defer func() {
 		// Pop current local scoped values after the iteration
-		t.locals = t.locals[0 : len(t.locals)-1]
+		c.locals = c.locals[0 : len(c.locals)-1]
 	}()
 
-	ret, err := t.render(node.Block)
-	if err != nil {
-		return "", errors.WithStack(err)
-	}
-	return ret, nil
+	return c.renderBlock(w, node.Block, node.Token.RightTrim, node.End.Token.LeftTrim)
 }
 
 // Render the "if" syntax
-func (t *Template) renderIfControl(node *ast.If) (string, error) {
-	cond, err := t.evaluateExpression(node.Condition)
+func (c *renderContext) renderIfControl(w *writer, node *ast.If) error {
+	cond, err := c.evaluateExpression(node.Condition)
 	if err != nil {
-		return "", errors.WithStack(err)
+		return errors.WithStack(err)
 	}
 
 	truthy, err := value.IsThuthy(cond)
 	if err != nil {
-		return "", errors.WithStack(&RenderError{
+		return errors.WithStack(&RenderError{
 			Token:   node.Condition.GetToken(),
 			Message: err.Error(),
 		})
 	}
 
-	var leftTrim, rightTrim bool
-
 	// If first if condition could evaluate as "true", render consequence block
 	if truthy {
-		v, err := t.render(node.Consequence)
-		if err != nil {
-			return "", errors.WithStack(err)
-		}
-
-		leftTrim = node.Token.RightTrim
-
+		var rightTrim bool
 		switch {
 		case len(node.Another) > 0:
 			rightTrim = node.Another[0].Token.LeftTrim
 		case node.Alternative != nil:
 			rightTrim = node.Alternative.Token.LeftTrim
-		case node.End.Token.LeftTrim:
-			rightTrim = true
-		}
-
-		switch {
-		case leftTrim && rightTrim:
-			v = strings.TrimSpace(v)
-		case leftTrim:
-			v = trimLeftSpace(v)
-		case rightTrim:
-			v = trimRightSpace(v)
+		default:
+			rightTrim = node.End.Token.LeftTrim
 		}
-		return v, nil
+		return c.renderBlock(w, node.Consequence, node.Token.RightTrim, rightTrim)
 	}
 
 	// Evaluate else if syntax as possible as we find
 	for i := range node.Another {
 		n := node.Another[i]
-		cond, err := t.evaluateExpression(n.Condition)
+		cond, err := c.evaluateExpression(n.Condition)
 		if err != nil {
-			return "", errors.WithStack(err)
+			return errors.WithStack(err)
 		}
 		truthy, err := value.IsThuthy(cond)
 		if err != nil {
-			return "", errors.WithStack(&RenderError{
-				Token:   node.Condition.GetToken(),
+			return errors.WithStack(&RenderError{
+				Token:   n.Condition.GetToken(),
 				Message: err.Error(),
 			})
 		}
 		if truthy {
-			v, err := t.render(n.Consequence)
-			if err != nil {
-				return "", errors.WithStack(err)
-			}
-
-			leftTrim = node.Token.RightTrim
-
+			var rightTrim bool
 			switch {
-			case i+1 < len(node.Another)-1:
+			case i+1 < len(node.Another):
 				rightTrim = node.Another[i+1].Token.LeftTrim
 			case node.Alternative != nil:
 				rightTrim = node.Alternative.Token.LeftTrim
-			case node.End.Token.LeftTrim:
-				rightTrim = true
-			}
-
-			switch {
-			case leftTrim && rightTrim:
-				v = strings.TrimSpace(v)
-			case leftTrim:
-				v = trimLeftSpace(v)
-			case rightTrim:
-				v = trimRightSpace(v)
+			default:
+				rightTrim = node.End.Token.LeftTrim
 			}
-			return v, nil
+			return c.renderBlock(w, n.Consequence, n.Token.RightTrim, rightTrim)
 		}
 	}
 
 	// Evaluate else syntax if found
 	if node.Alternative != nil {
-		v, err := t.render(node.Alternative.Consequence)
-		if err != nil {
-			return "", errors.WithStack(err)
-		}
+		return c.renderBlock(
+			w,
+			node.Alternative.Consequence,
+			node.Alternative.Token.RightTrim,
+			node.End.Token.LeftTrim,
+		)
+	}
 
-		leftTrim = node.Alternative.Token.RightTrim
-		rightTrim = node.End.Token.LeftTrim
+	return nil
+}
 
-		switch {
-		case leftTrim && rightTrim:
-			v = strings.TrimSpace(v)
-		case leftTrim:
-			v = trimLeftSpace(v)
-		case rightTrim:
-			v = trimRightSpace(v)
-		}
-		return v, nil
+// Render inside block of control with trimming whitespaces which are specified by surrounded controls
+func (c *renderContext) renderBlock(w *writer, nodes []ast.Node, leftTrim, rightTrim bool) error {
+	if leftTrim {
+		w.trimNextLeft()
 	}
-
-	return "", nil
+	if err := c.render(w, nodes); err != nil {
+		return errors.WithStack(err)
+	}
+	if rightTrim {
+		w.trimRight()
+	}
+	return nil
 }
@@ -1,10 +1,14 @@
 package tender
 
 import (
+	"math"
 	"os"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 	"github.com/ysugimoto/tender/ast"
 	"github.com/ysugimoto/tender/lexer"
 )
@@ -98,6 +102,53 @@ func TestInterporation(t *testing.T) {
 	}
 }
 
+func TestInterporationExpression(t *testing.T) {
+	os.Setenv("FOO_BAR", "baz")
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "string literal", input: `${"literal"}`, expect: "literal"},
+		{name: "int literal", input: `${1}`, expect: "1"},
+		{name: "float literal", input: `${1.5}`, expect: "1.5"},
+		{name: "bool literal", input: `${true}`, expect: "true"},
+		{name: "prefix expression", input: `${-n}`, expect: "-10"},
+		{name: "not expression", input: `${!b}`, expect: "false"},
+		{name: "grouped expression", input: `${(v)}`, expect: "foo"},
+		{name: "equal expression", input: `${v == "foo"}`, expect: "true"},
+		{name: "complicated expression", input: `${(n > 5 && b) || v != "foo"}`, expect: "true"},
+		{name: "environment variable in expression", input: `${FOO_BAR == "baz"}`, expect: "true"},
+		{name: "with spaces", input: `${ v }`, expect: "foo"},
+		{name: "trimming", input: "a \n ${~ v ~} \n b", expect: "afoob"},
+		{name: "undefined variable", input: `${undefined}`, isError: true},
+		{name: "invalid expression", input: `${v ==}`, isError: true},
+		{name: "unclosed interporation", input: `${v`, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := Variables{"v": "foo", "n": 10, "b": true}
+			rendered, err := NewFromString(tt.input).With(vars).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
 func TestForSliceLoop(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -184,6 +235,119 @@ Index is ${i}, value is ${v}
 	}
 }
 
+func TestForExpressionLoop(t *testing.T) {
+	functions := map[string]any{
+		"keys": func(m map[string]any) []string {
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			return keys
+		},
+		"one": func() int { return 1 },
+		"count": func(n int) []int {
+			list := make([]int, n)
+			for i := range list {
+				list[i] = i
+			}
+			return list
+		},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "function call", input: `%{ for _, k in keys(m) }${k},%{ endfor }`, expect: "a,b,"},
+		{name: "function call with number", input: `%{ for i in count(3) }${i}%{ endfor }`, expect: "012"},
+		{name: "list literal", input: `%{ for i, v in ["dev", "prod"] }${i}=${v},%{ endfor }`, expect: "0=dev,1=prod,"},
+		{name: "object literal", input: `%{ for k, v in { b = 2, a = 1 } }${k}=${v},%{ endfor }`, expect: "a=1,b=2,"},
+		{name: "nested list literal", input: `%{ for _, l in [[1, 2], [3]] }%{ for _, v in l }${v}%{ endfor };%{ endfor }`, expect: "12;3;"},
+		{name: "conditional", input: `%{ for _, v in flag ? ["x"] : ["y"] }${v}%{ endfor }`, expect: "x"},
+		{name: "pipeline", input: `%{ for _, k in m | keys }${k}%{ endfor }`, expect: "ab"},
+		{name: "loop inside if", input: `%{ if flag }%{ for _, v in ["x", "y"] }${v}%{ endfor }%{ endif }`, expect: "xy"},
+		{name: "not iterable", input: `%{ for v in one() }${v}%{ endfor }`, isError: true},
+		{name: "not iterable expression", input: `%{ for v in flag ? "x" : "y" }${v}%{ endfor }`, isError: true},
+		{name: "undefined function", input: `%{ for v in undefined(m) }${v}%{ endfor }`, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := Variables{"m": map[string]any{"b": 2, "a": 1}, "flag": true}
+			rendered, err := NewFromString(tt.input, WithFunctions(functions)).With(vars).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestForNotIterableErrorPosition(t *testing.T) {
+	opt := WithFunctions(map[string]any{
+		"one": func() int { return 1 },
+	})
+	_, err := NewFromString("line1\n%{ for v in one() }${v}%{ endfor }", opt).Render()
+	if err == nil {
+		t.Errorf("Expects error, but got-nil")
+		return
+	}
+	var re *RenderError
+	if !errors.As(err, &re) {
+		t.Errorf("Expects RenderError, but got %T", err)
+		return
+	}
+	if diff := cmp.Diff([]int{2, 13}, []int{re.Token.Line, re.Token.Position}); diff != "" {
+		t.Errorf("Error position mismatch, diff=%s", diff)
+	}
+}
+
+func TestForNotIterableErrorMessage(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{input: `%{ for v in count }%{ endfor }`, expect: `"count" is not iterable`},
+		{input: `%{ for v in list[i].name }%{ endfor }`, expect: `"list[i].name" is not iterable`},
+		{input: `%{ for v in list?.[i + 0]?.name }%{ endfor }`, expect: `For collection expression is not iterable`},
+		{input: `%{ for v in list[i]["name"] }%{ endfor }`, expect: `"list[i]["name"]" is not iterable`},
+		{input: `%{ for v in count + 1 }%{ endfor }`, expect: `For collection expression is not iterable`},
+		{input: `${[for v in list[i]?.name : v]}`, expect: `"list[i]?.name" is not iterable`},
+		{input: `${[for v in count * 2 : v]}`, expect: `For collection expression is not iterable`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			vars := Variables{
+				"count": 1,
+				"i":     0,
+				"list":  []map[string]string{{"name": "foo"}},
+			}
+			_, err := NewFromString(tt.input).With(vars).Render()
+			var re *RenderError
+			if !errors.As(err, &re) {
+				t.Errorf("Expects RenderError, but got %T", err)
+				return
+			}
+			if !strings.HasPrefix(re.Message, tt.expect) {
+				t.Errorf("Error message mismatch, expect=%s, actual=%s", tt.expect, re.Message)
+			}
+		})
+	}
+}
+
 func TestForMapLoop(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -583,3 +747,658 @@ That's all, very simplified!
 		NewFromString(input).With(vars).Render()
 	}
 }
+
+func TestArithmeticExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "add", input: `${count + 1}`, expect: "11"},
+		{name: "subtract", input: `${count - 1}`, expect: "9"},
+		{name: "multiply", input: `${count * 2}`, expect: "20"},
+		{name: "divide", input: `${count / 4}`, expect: "2.5"},
+		{name: "modulo", input: `${count % 3}`, expect: "1"},
+		{name: "float promotion", input: `${count + 0.5}`, expect: "10.5"},
+		{name: "precedence", input: `${1 + count * 2 - 6 / 3}`, expect: "19"},
+		{name: "grouped precedence", input: `${(1 + count) * 2}`, expect: "22"},
+		{name: "prefix minus", input: `${-count + 1}`, expect: "-9"},
+		{name: "comparison", input: `${count + 1 > 10}`, expect: "true"},
+		{name: "modulo comparison in if", input: `%{ if count % 2 == 0 }even%{ else }odd%{ endif }`, expect: "even"},
+		{name: "division by zero", input: `${count / 0}`, isError: true},
+		{name: "modulo by zero", input: `${count % 0}`, isError: true},
+		{name: "not numeric", input: `${count + "1"}`, isError: true},
+		{name: "max integer", input: `${9223372036854775806 + 1}`, expect: "9223372036854775807"},
+		{name: "add overflow", input: `${9223372036854775807 + 1}`, isError: true},
+		{name: "subtract overflow", input: `${-9223372036854775807 - count}`, isError: true},
+		{name: "multiply overflow", input: `${4611686018427387904 * 2}`, isError: true},
+		{name: "large unsigned integer", input: `${big + 1}`, isError: true},
+		{name: "large unsigned integer comparison", input: `${big > 0}`, isError: true},
+		{name: "unsigned integer within int64", input: `${half + 1}`, expect: "9223372036854775807"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := Variables{
+				"count": 10,
+				"big":   uint64(math.MaxUint64),
+				"half":  uint64(math.MaxInt64 - 1),
+			}
+			rendered, err := NewFromString(tt.input).With(vars).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestArithmeticErrorPosition(t *testing.T) {
+	_, err := NewFromString("line1\nvalue is ${count / 0}").With(Variables{"count": 1}).Render()
+	if err == nil {
+		t.Errorf("Expects error, but got-nil")
+		return
+	}
+	var re *RenderError
+	if !errors.As(err, &re) {
+		t.Errorf("Expects RenderError, but got %T", err)
+		return
+	}
+	if diff := cmp.Diff([]int{2, 18}, []int{re.Token.Line, re.Token.Position}); diff != "" {
+		t.Errorf("Error position mismatch, diff=%s", diff)
+	}
+}
+
+func TestArithmeticOverflowPosition(t *testing.T) {
+	_, err := NewFromString("line1\nvalue is ${9223372036854775807 + count}").With(Variables{"count": 1}).Render()
+	if err == nil {
+		t.Errorf("Expects error, but got-nil")
+		return
+	}
+	var re *RenderError
+	if !errors.As(err, &re) {
+		t.Errorf("Expects RenderError, but got %T", err)
+		return
+	}
+	if diff := cmp.Diff([]int{2, 32}, []int{re.Token.Line, re.Token.Position}); diff != "" {
+		t.Errorf("Error position mismatch, diff=%s", diff)
+	}
+}
+
+func TestConditionalExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "true", input: `${v == "foo" ? "yes" : "no"}`, expect: "yes"},
+		{name: "false", input: `${v != "foo" ? "yes" : "no"}`, expect: "no"},
+		{name: "string condition", input: `${v ? v : "empty"}`, expect: "foo"},
+		{name: "nested", input: `${n > 10 ? "large" : n > 5 ? "middle" : "small"}`, expect: "middle"},
+		{name: "with arithmetic", input: `${n % 2 == 0 ? n / 2 : n * 3 + 1}`, expect: "4"},
+		{name: "in if condition", input: `%{ if n > 5 ? true : false }ok%{ endif }`, expect: "ok"},
+		{name: "unselected branch is not evaluated", input: `${true ? "ok" : undefined}`, expect: "ok"},
+		{name: "selected branch error", input: `${false ? "ok" : undefined}`, isError: true},
+		{name: "not truthy condition", input: `${n ? "yes" : "no"}`, isError: true},
+		{name: "missing alternative", input: `${v ? "yes"}`, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := NewFromString(tt.input).With(Variables{"v": "foo", "n": 8}).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestCollectionLiteral(t *testing.T) {
+	functions := map[string]any{
+		"count": func(values []any) int { return len(values) },
+		"get":   func(m map[string]any, key string) any { return m[key] },
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "list", input: `${["a", 1, true]}`, expect: "[a, 1, true]"},
+		{name: "empty list", input: `${[]}`, expect: "[]"},
+		{name: "list with expressions", input: `${[v, n * 2, n > 5 ? "large" : "small",]}`, expect: "[foo, 16, large]"},
+		{name: "nested list", input: `${[[1, 2], [3]]}`, expect: "[[1, 2], [3]]"},
+		{name: "multi-line list", input: "${[\n  \"a\",\n  \"b\",\n]}", expect: "[a, b]"},
+		{name: "object", input: `${{ key = v, "k2": n }}`, expect: "{k2: 8, key: foo}"},
+		{name: "empty object", input: `${{}}`, expect: "{}"},
+		{name: "object with expression key", input: `${{ (v) = 1 }}`, expect: "{foo: 1}"},
+		{name: "multi-line object", input: "${{\n  a = 1\n  b = [2]\n}}", expect: "{a: 1, b: [2]}"},
+		{name: "nested object", input: `${{ a = { b = "c" } }}`, expect: "{a: {b: c}}"},
+		{name: "object with trim marker", input: `${~ { a = 1 } ~}`, expect: "{a: 1}"},
+		{name: "list argument", input: `${count(["a", "b"])}`, expect: "2"},
+		{name: "object argument", input: `${get({ a = v }, "a")}`, expect: "foo"},
+		{name: "literal in if condition", input: `%{ if count([v]) == 1 }ok%{ endif }`, expect: "ok"},
+		{name: "duplicate object key", input: `${{ a = 1, "a" = 2 }}`, isError: true},
+		{name: "invalid object key type", input: `${{ ([1]) = 1 }}`, isError: true},
+		{name: "undefined variable in list", input: `${[undefined]}`, isError: true},
+		{name: "unclosed list", input: `${["a"}`, isError: true},
+		{name: "missing list comma", input: `${["a" "b"]}`, isError: true},
+		{name: "missing object separator", input: `${{ a 1 }}`, isError: true},
+		{name: "variable accessor as object key", input: `${{ a.b = 1 }}`, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := NewFromString(tt.input, WithFunctions(functions)).With(Variables{"v": "foo", "n": 8}).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestForExpression(t *testing.T) {
+	functions := map[string]any{
+		"upper": strings.ToUpper,
+		"count": func(values []any) int { return len(values) },
+		"first": func(s string) string { return s[0:1] },
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "list result", input: `${[for s in list : upper(s)]}`, expect: "[A, , B]"},
+		{name: "list result with condition", input: `${[for s in list : upper(s) if s != ""]}`, expect: "[A, B]"},
+		{name: "list result with index", input: `${[for i, s in list : i if s != ""]}`, expect: "[0, 2]"},
+		{name: "list result from map", input: `${[for k, v in m : k if v > 1]}`, expect: "[b]"},
+		{name: "object result", input: `${{for k, v in m : v => k}}`, expect: "{1: a, 2: b}"},
+		{name: "object result from list", input: `${{for s in list : s => upper(s) if s != ""}}`, expect: "{a: A, b: B}"},
+		{name: "grouping mode", input: `${{for s in fruits : first(s) => s...}}`, expect: "{a: [apple, avocado], b: [banana]}"},
+		{name: "nested for-expression", input: `${[for l in [[1, 2], [3]] : [for v in l : v * count(l)]]}`, expect: "[[2, 4], [3]]"},
+		{name: "as for control collection", input: `%{ for _, v in [for s in list : s if s != ""] }${v};%{ endfor }`, expect: "a;b;"},
+		{name: "as function argument", input: `${count([for s in list : s if s != ""])}`, expect: "2"},
+		{name: "duplicate key without grouping", input: `${{for s in fruits : first(s) => s}}`, isError: true},
+		{name: "invalid key type", input: `${{for s in list : [s] => s}}`, isError: true},
+		{name: "not iterable collection", input: `${[for s in "foo" : s]}`, isError: true},
+		{name: "not truthy condition", input: `${[for i, s in list : s if i]}`, isError: true},
+		{name: "loop variable is not leaked", input: `${[for s in list : s]}${s}`, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := Variables{
+				"list":   []string{"a", "", "b"},
+				"m":      map[string]int{"a": 1, "b": 2},
+				"fruits": []any{"apple", "avocado", "banana"},
+			}
+			rendered, err := NewFromString(tt.input, WithFunctions(functions)).With(vars).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestSplatExpression(t *testing.T) {
+	type server struct {
+		Name string
+		Tags []string
+	}
+	functions := map[string]any{
+		"join": func(sep string, values []string) string { return strings.Join(values, sep) },
+		"values": func(m map[string][]*server) [][]*server {
+			return [][]*server{m["g"]}
+		},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "full splat", input: `${join(", ", servers[*].Name)}`, expect: "a, b"},
+		{name: "legacy splat", input: `${join(", ", servers.*.Name)}`, expect: "a, b"},
+		{name: "splat in for control", input: `%{ for _, n in servers[*].Name }${n};%{ endfor }`, expect: "a;b;"},
+		{name: "splat for non-list value", input: `${single[*].Name}`, expect: "[only]"},
+		{name: "splat for undefined field", input: `${servers[*].Undefined}`, isError: true},
+		{name: "splat after dynamic index", input: `${join(", ", servers[i].Tags[*])}`, expect: "x, y"},
+		{name: "splat after dynamic key", input: `${join(", ", groups[k][*].Name)}`, expect: "c, d"},
+		{name: "splat after function call", input: `${join(", ", values(groups)[0][*].Name)}`, expect: "c, d"},
+		{name: "dynamic index for each element", input: `${join(", ", servers[*].Tags[i])}`, expect: "x, z"},
+		{name: "legacy splat after dynamic key", input: `${join(", ", groups[k].*.Name)}`, expect: "c, d"},
+		{name: "index after legacy splat", input: `${groups[k].*.Name[i]}`, expect: "c"},
+		{name: "nested splat", input: `${servers[*].Tags[*]}`, expect: "[[x, y], [z]]"},
+		{name: "splat for null", input: `${nothing[*]}`, expect: "[]"},
+		{name: "splat not closed", input: `${servers[i][*}`, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := Variables{
+				"servers": []*server{{Name: "a", Tags: []string{"x", "y"}}, {Name: "b", Tags: []string{"z"}}},
+				"single":  server{Name: "only"},
+				"groups":  map[string][]*server{"g": {{Name: "c"}, {Name: "d"}}},
+				"nothing": nil,
+				"i":       0,
+				"k":       "g",
+			}
+			rendered, err := NewFromString(tt.input, WithFunctions(functions)).With(vars).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestIndexExpression(t *testing.T) {
+	type server struct {
+		Name   string
+		secret string
+	}
+	type Embedded struct {
+		Name string
+	}
+	type wrapper struct {
+		*Embedded
+		X int
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "map with variable key", input: `${regions[region]}`, expect: "tokyo"},
+		{name: "map with converted key", input: `${codes[i + 1]}`, expect: "one"},
+		{name: "slice with variable index", input: `${list[i]}`, expect: "a"},
+		{name: "slice with numeric string index", input: `${list["1"]}`, expect: "b"},
+		{name: "struct field", input: `${servers[i].Name}`, expect: "web"},
+		{name: "struct field with string index", input: `${servers[i]["Name"]}`, expect: "web"},
+		{name: "index in for control", input: `%{ for i, v in list }${list[i] == v}%{ endfor }`, expect: "truetrue"},
+		{name: "index for literal", input: `${["x", "y"][i + 1]}`, expect: "y"},
+		{name: "static map key conversion", input: `${codes[1]}`, expect: "one"},
+		{name: "undefined key", input: `${regions[i]}`, isError: true},
+		{name: "index out of range", input: `${list[i + 2]}`, isError: true},
+		{name: "negative index", input: `${list[-1]}`, isError: true},
+		{name: "non-integer index", input: `${list[0.5]}`, isError: true},
+		{name: "unexported field", input: `${servers[i].secret}`, isError: true},
+		{name: "not indexable", input: `${region[i]}`, isError: true},
+		{name: "field of embedded struct", input: `${embedded[i][key]}`, expect: "web"},
+		{name: "field through nil embedded pointer", input: `${wrapper[key]}`, isError: true},
+		{name: "field through nil embedded pointer with static path", input: `${wrapper.Name}`, isError: true},
+		{name: "optional field through nil embedded pointer", input: `[${wrapper?.[key]}]`, expect: "[]"},
+		{name: "sibling field of nil embedded pointer", input: `${wrapper[x]}`, expect: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := Variables{
+				"regions":  map[string]string{"ap": "tokyo"},
+				"region":   "ap",
+				"codes":    map[int]string{1: "one"},
+				"list":     []string{"a", "b"},
+				"servers":  []*server{{Name: "web"}},
+				"i":        0,
+				"embedded": []wrapper{{Embedded: &Embedded{Name: "web"}}},
+				"wrapper":  wrapper{X: 1},
+				"key":      "Name",
+				"x":        "X",
+			}
+			rendered, err := NewFromString(tt.input).With(vars).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestIndexErrorPosition(t *testing.T) {
+	_, err := NewFromString("line1\n${ list[i + 5] }").With(Variables{"list": []int{1}, "i": 0}).Render()
+	if err == nil {
+		t.Errorf("Expects error, but got-nil")
+		return
+	}
+	var re *RenderError
+	if !errors.As(err, &re) {
+		t.Errorf("Expects RenderError, but got %T", err)
+		return
+	}
+	if diff := cmp.Diff([]int{2, 11}, []int{re.Token.Line, re.Token.Position}); diff != "" {
+		t.Errorf("Error position mismatch, diff=%s", diff)
+	}
+}
+
+func TestIndexOutOfRangeError(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		position []int
+	}{
+		{name: "static index", input: "line1\n${ list[5] }", position: []int{2, 4}},
+		{name: "dynamic index", input: "line1\n${ list[i] }", position: []int{2, 9}},
+		{name: "static index after dynamic index", input: "line1\n${ nested[0][5] }", position: []int{2, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := Variables{
+				"list":   []int{1},
+				"nested": [][]int{{1}},
+				"i":      5,
+			}
+			_, err := NewFromString(tt.input).With(vars).Render()
+			var re *RenderError
+			if !errors.As(err, &re) {
+				t.Errorf("Expects RenderError, but got %T", err)
+				return
+			}
+			if diff := cmp.Diff("Index 5 is out of range for length 1", re.Message); diff != "" {
+				t.Errorf("Error message mismatch, diff=%s", diff)
+			}
+			if diff := cmp.Diff(tt.position, []int{re.Token.Line, re.Token.Position}); diff != "" {
+				t.Errorf("Error position mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestStringTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    []RenderOption
+		expect  string
+		isError bool
+	}{
+		{name: "escape sequences", input: `${"a\"b\\c\tdé\U0001F600"}`, expect: "a\"b\\c\tdé\U0001F600"},
+		{name: "escaped template sequences", input: `${"$${name} %%{ if } $5 100%"}`, expect: "${name} %{ if } $5 100%"},
+		{name: "interporation", input: `${"Hello, ${name}!"}`, expect: "Hello, tender!"},
+		{name: "comparison", input: `${"Hi ${name}" == "Hi tender"}`, expect: "true"},
+		{name: "nested string template", input: `${"[${"<${name}>"}]"}`, expect: "[<tender>]"},
+		{name: "if control", input: `${"%{ if ok }yes%{ else }no%{ endif }"}`, expect: "yes"},
+		{name: "for control with trim", input: `${"%{ for _, v in list ~} ${v},%{ endfor }"}`, expect: "a,b,"},
+		{name: "object key", input: `${{ "${name}_key" = 1 }["tender_key"]}`, expect: "1"},
+		{name: "escape once", input: `${"<${tag}>"}`, opts: []RenderOption{WithHtmlEscape()}, expect: "&lt;&amp;&gt;"},
+		{name: "unterminated string", input: `${"abc}`, isError: true},
+		{name: "invalid escape", input: `${"\d"}`, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := Variables{
+				"name": "tender",
+				"ok":   true,
+				"list": []string{"a", "b"},
+				"tag":  "&",
+			}
+			rendered, err := NewFromString(tt.input, tt.opts...).With(vars).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestStringTemplateErrorPosition(t *testing.T) {
+	_, err := NewFromString("line1\n${ \"a ${ list[i + 5] }\" }").With(Variables{"list": []int{1}, "i": 0}).Render()
+	if err == nil {
+		t.Errorf("Expects error, but got-nil")
+		return
+	}
+	var re *RenderError
+	if !errors.As(err, &re) {
+		t.Errorf("Expects RenderError, but got %T", err)
+		return
+	}
+	if diff := cmp.Diff([]int{2, 17}, []int{re.Token.Line, re.Token.Position}); diff != "" {
+		t.Errorf("Error position mismatch, diff=%s", diff)
+	}
+}
+
+func TestHeredoc(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "heredoc", input: "${<<EOT\nHello, ${name}!\n  $${raw}\nEOT\n}", expect: "Hello, tender!\n  ${raw}\n"},
+		{name: "empty heredoc", input: "${<<EOT\nEOT\n}", expect: ""},
+		{name: "body line starts with marker", input: "${<<EOT\nEOT is here\nEOT, more\nEOT\n}", expect: "EOT is here\nEOT, more\n"},
+		{
+			name:   "indented heredoc",
+			input:  "${ <<-EOT\n    %{ for _, v in list ~}\n    - ${v}\n    %{ endfor ~}\n    EOT\n}",
+			expect: "- a\n- b\n",
+		},
+		{name: "function argument", input: "${ length(<<-EOT\n  ab\n  EOT\n) }", expect: "3"},
+		{name: "object value", input: "${ { text = <<-EOT\n    x\n    EOT\n}.text }", expect: "x\n"},
+		{name: "unterminated heredoc", input: "${<<EOT\nabc\n}", isError: true},
+		{name: "missing newline", input: "${<<EOT abc }", isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := Variables{
+				"name": "tender",
+				"list": []string{"a", "b"},
+			}
+			rendered, err := NewFromString(tt.input, WithFunctions(map[string]any{
+				"length": func(s string) int { return len(s) },
+			})).With(vars).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestNull(t *testing.T) {
+	type user struct {
+		Name string
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "null literal", input: `${null}`, expect: ""},
+		{name: "nil pointer", input: `[${user}]`, expect: "[]"},
+		{name: "nil map", input: `[${tags}]`, expect: "[]"},
+		{name: "nil interface", input: `[${any}]`, expect: "[]"},
+		{name: "compare with null", input: `${user == null}, ${tags != null}, ${name == null}`, expect: "true, false, false"},
+		{name: "conditional with null", input: `${user == null ? "guest" : user.Name}`, expect: "guest"},
+		{name: "null in list", input: `${[1, null, 2]}`, expect: "[1, , 2]"},
+		{name: "null in object", input: `${{ a = null }.a == null}`, expect: "true"},
+		{name: "attribute of nil pointer", input: `${user.Name}`, isError: true},
+		{name: "index of null", input: `${null["key"]}`, isError: true},
+		{name: "null condition", input: `${null ? 1 : 2}`, isError: true},
+		{name: "null in if control", input: `%{ if user }x%{ endif }`, isError: true},
+		{name: "null with logical operator", input: `${true && null}`, isError: true},
+		{name: "non-bool with logical operator", input: `${name || true}`, isError: true},
+		{name: "null with arithmetic", input: `${null + 1}`, isError: true},
+		{name: "null with ordering", input: `${user > 1}`, isError: true},
+		{name: "null with prefix", input: `${!null}`, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nilUser *user
+			var nilAny any
+			vars := Variables{
+				"user": nilUser,
+				"tags": map[string]string(nil),
+				"any":  nilAny,
+				"name": "tender",
+			}
+			rendered, err := NewFromString(tt.input).With(vars).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestCoalesceAndOptional(t *testing.T) {
+	type profile struct {
+		Nick string
+	}
+	type user struct {
+		Profile *profile
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		isError bool
+	}{
+		{name: "undefined variable", input: `${undefined ?? "default"}`, expect: "default"},
+		{name: "undefined key", input: `${config.missing ?? "default"}`, expect: "default"},
+		{name: "defined value", input: `${config.name ?? "default"}`, expect: "tender"},
+		{name: "false is not replaced", input: `${config.enabled ?? true}`, expect: "false"},
+		{name: "null value", input: `${null ?? 1 + 2}`, expect: "3"},
+		{name: "dynamic index", input: `${list[i + 5] ?? "none"}`, expect: "none"},
+		{name: "chained coalesce", input: `${a ?? b ?? "c"}`, expect: "c"},
+		{name: "with conditional", input: `${config.name ?? "x" == "tender" ? "yes" : "no"}`, expect: "yes"},
+		{name: "optional attribute", input: `[${config?.missing?.nested}]`, expect: "[]"},
+		{name: "optional nil pointer", input: `${user.Profile?.Nick ?? "anonymous"}`, expect: "anonymous"},
+		{name: "optional dynamic index", input: `${list?.[i + 5]?.name ?? "none"}`, expect: "none"},
+		{name: "optional after dynamic index", input: `${list[i]?.name}`, expect: "foo"},
+		{name: "optional static index", input: `${list?.[0]?.["name"]}`, expect: "foo"},
+		{name: "only guards own access", input: `${config?.missing.nested}`, isError: true},
+		{name: "optional empty struct field", input: `[${user?.[""]}]`, expect: "[]"},
+		{name: "empty struct field", input: `${user[""]}`, isError: true},
+		{name: "dynamic empty struct field", input: `${user[config.empty]}`, isError: true},
+		{name: "type error is not replaced", input: `${config.name + 1 ?? 0}`, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := Variables{
+				"config": map[string]any{
+					"name":    "tender",
+					"enabled": false,
+					"empty":   "",
+				},
+				"list": []map[string]string{
+					{"name": "foo"},
+				},
+				"i":    0,
+				"user": user{},
+			}
+			rendered, err := NewFromString(tt.input).With(vars).Render()
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
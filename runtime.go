@@ -0,0 +1,105 @@
+package tender
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type runtimeKey struct{}
+
+// runtime struct holds per-rendering values which functions can access through context.Context
+type runtime struct {
+	options   options
+	startedAt time.Time
+	// nested reports rendering is processing in RenderFile()
+	nested bool
+}
+
+func newRuntimeContext(o options) context.Context {
+	rt := &runtime{options: o}
+	rt.startedAt = rt.now()
+	return context.WithValue(context.Background(), runtimeKey{}, rt)
+}
+
+func (rt *runtime) now() time.Time {
+	if rt.options.clock != nil {
+		return rt.options.clock()
+	}
+	return time.Now()
+}
+
+// Now returns the current time from the rendering clock.
+// This function is intended to be used in functions which receive context.Context,
+// and returns time.Now() if the context is not provided by rendering.
+func Now(ctx context.Context) time.Time {
+	if rt, ok := ctx.Value(runtimeKey{}).(*runtime); ok {
+		return rt.now()
+	}
+	return time.Now()
+}
+
+// StartedAt returns the time when rendering started.
+// The time is the same during single rendering, like Terraform's plantimestamp.
+func StartedAt(ctx context.Context) time.Time {
+	if rt, ok := ctx.Value(runtimeKey{}).(*runtime); ok {
+		return rt.startedAt
+	}
+	return time.Now()
+}
+
+// FileSystem returns the filesystem which is provided by WithFileSystem() option.
+// It returns nil if the filesystem is not provided, so functions must not access any files.
+func FileSystem(ctx context.Context) fs.FS {
+	if rt, ok := ctx.Value(runtimeKey{}).(*runtime); ok {
+		return rt.options.fileSystem
+	}
+	return nil
+}
+
+// RenderFile renders the template file in the filesystem with provided variables.
+// The template is rendered with the same options as the current rendering, like Terraform's templatefile.
+// HTML escape is not applied to the result because it is applied when the result is interporated.
+// Calling RenderFile from the function inside RenderFile is not allowed to avoid infinite recursion.
+func RenderFile(ctx context.Context, path string, vars Variables) (string, error) {
+	rt, ok := ctx.Value(runtimeKey{}).(*runtime)
+	switch {
+	case !ok:
+		return "", errors.New("RenderFile must be called from the function in rendering")
+	case rt.nested:
+		return "", errors.New("cannot render template file recursively")
+	case rt.options.fileSystem == nil:
+		return "", errors.New("filesystem is not provided, it must be specified by WithFileSystem option")
+	}
+
+	fp, err := rt.options.fileSystem.Open(path)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer fp.Close()
+
+	compiled, err := Compile(fp)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	nested := *rt
+	nested.nested = true
+	c := newRenderContext(variablesToValue(vars))
+	c.options = rt.options
+	// Rendered string is escaped by the caller's interporation, so the nested rendering must not escape twice
+	c.enableEscape = false
+	c.ctx = context.WithValue(ctx, runtimeKey{}, &nested)
+
+	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
+	defer pool.Put(buf)
+
+	buf.Reset()
+	if err := c.execute(buf, compiled.nodes); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,93 @@
+package tender
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"partial.tpl": {Data: []byte(`${~ greet(name) ~}`)},
+		"recurse.tpl": {Data: []byte(`${include("partial.tpl")}`)},
+		"escape.tpl":  {Data: []byte(`<i>${name}</i>`)},
+	}
+	functions := WithFunctions(map[string]any{
+		"greet": func(name string) string { return "Hello " + name },
+		"include": func(ctx context.Context, path string) (string, error) {
+			return RenderFile(ctx, path, Variables{"name": "partial"})
+		},
+		"templatefile": func(ctx context.Context, path, name string) (string, error) {
+			return RenderFile(ctx, path, Variables{"name": name})
+		},
+	})
+
+	tests := []struct {
+		name    string
+		input   string
+		options []RenderOption
+		expect  string
+		isError bool
+	}{
+		{
+			name:    "render file with the same functions",
+			input:   `[${include("partial.tpl")}]`,
+			options: []RenderOption{functions, WithFileSystem(fsys)},
+			expect:  "[Hello partial]",
+		},
+		{
+			name:    "html escape is applied once",
+			input:   `${templatefile("escape.tpl", "<b>")}`,
+			options: []RenderOption{functions, WithFileSystem(fsys), WithHtmlEscape()},
+			expect:  "&lt;i&gt;&lt;b&gt;&lt;/i&gt;",
+		},
+		{
+			name:    "filesystem is not provided",
+			input:   `${include("partial.tpl")}`,
+			options: []RenderOption{functions},
+			isError: true,
+		},
+		{
+			name:    "file not found",
+			input:   `${include("missing.tpl")}`,
+			options: []RenderOption{functions, WithFileSystem(fsys)},
+			isError: true,
+		},
+		{
+			name:    "recursive rendering",
+			input:   `${include("recurse.tpl")}`,
+			options: []RenderOption{functions, WithFileSystem(fsys)},
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := Render(tt.input, nil, tt.options...)
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected render error\n %+v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, rendered); diff != "" {
+				t.Errorf("Rendered string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderFileOutsideOfRendering(t *testing.T) {
+	if _, err := RenderFile(context.Background(), "partial.tpl", nil); err == nil {
+		t.Errorf("Expects error but got nil")
+	}
+	if FileSystem(context.Background()) != nil {
+		t.Errorf("FileSystem should be nil outside of rendering")
+	}
+}
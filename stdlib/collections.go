@@ -0,0 +1,506 @@
+package stdlib
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender/value"
+)
+
+// Maximum number of elements which range function can produce, the same as Terraform
+const maxRangeLength = 1024
+
+// Collections returns Terraform compatible collection functions.
+// Functions accept any slice, array, map and struct values which are resolved from template variables.
+// Set functions treat lists as sets, and the order of result elements follows the first appearance.
+func Collections() map[string]any {
+	return map[string]any{
+		"length":          length,
+		"keys":            keys,
+		"values":          values,
+		"lookup":          lookup,
+		"merge":           merge,
+		"concat":          concat,
+		"contains":        contains,
+		"distinct":        distinct,
+		"flatten":         flatten,
+		"sort":            sortList,
+		"reverse":         reverse,
+		"slice":           slice,
+		"zipmap":          zipmap,
+		"element":         element,
+		"index":           index,
+		"coalesce":        coalesce,
+		"coalescelist":    coalescelist,
+		"compact":         compact,
+		"range":           rangeList,
+		"chunklist":       chunklist,
+		"setunion":        setunion,
+		"setintersection": setintersection,
+		"setsubtract":     setsubtract,
+		"alltrue":         alltrue,
+		"anytrue":         anytrue,
+		"sum":             sum,
+	}
+}
+
+// Get length of list, map, struct or string
+func length(v any) (int, error) {
+	rv := indirect(reflect.ValueOf(v))
+	switch {
+	case !rv.IsValid():
+		return 0, errors.New("argument must not be null")
+	case rv.Kind() == reflect.String:
+		return utf8.RuneCountInString(rv.String()), nil
+	case isList(rv), rv.Kind() == reflect.Map:
+		return rv.Len(), nil
+	case rv.Kind() == reflect.Struct:
+		keys, _, err := toObject(v)
+		return len(keys), err
+	default:
+		return 0, errors.Errorf("argument must be a string, a collection type, or a structural type but got %s", typeName(rv))
+	}
+}
+
+// Get keys of map in lexicographical order
+func keys(v any) ([]string, error) {
+	keys, _, err := toObject(v)
+	return keys, err
+}
+
+// Get values of map in lexicographical order of its keys
+func values(v any) ([]any, error) {
+	keys, elements, err := toObject(v)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, len(keys))
+	for i := range keys {
+		values[i] = toInterface(elements[keys[i]])
+	}
+	return values, nil
+}
+
+// Get value from map by key, default value is returned if the key does not exist
+func lookup(v any, key string, defaults ...any) (any, error) {
+	if len(defaults) > 1 {
+		return nil, errors.New("lookup accepts at most one default value")
+	}
+	_, elements, err := toObject(v)
+	if err != nil {
+		return nil, err
+	}
+	if found, ok := elements[key]; ok {
+		return toInterface(found), nil
+	}
+	if len(defaults) == 0 {
+		return nil, errors.Errorf(`the given key "%s" does not identify an element in this collection value`, key)
+	}
+	return defaults[0], nil
+}
+
+// Merge maps into single map, later map overrides the same key
+func merge(maps ...any) (map[string]any, error) {
+	merged := map[string]any{}
+	for i := range maps {
+		if maps[i] == nil {
+			continue
+		}
+		keys, elements, err := toObject(maps[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %d", i+1)
+		}
+		for _, key := range keys {
+			merged[key] = toInterface(elements[key])
+		}
+	}
+	return merged, nil
+}
+
+// Combine lists into single list
+func concat(lists ...any) ([]any, error) {
+	combined := []any{}
+	for i := range lists {
+		elements, err := toList(lists[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %d", i+1)
+		}
+		for j := range elements {
+			combined = append(combined, toInterface(elements[j]))
+		}
+	}
+	return combined, nil
+}
+
+// Check list contains the value
+func contains(list, v any) (bool, error) {
+	elements, err := toList(list)
+	if err != nil {
+		return false, err
+	}
+	return indexOf(elements, reflect.ValueOf(v)) >= 0, nil
+}
+
+// Remove duplicate elements from list, the first occurrence is kept
+func distinct(list any) ([]any, error) {
+	elements, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	return uniqueElements(elements), nil
+}
+
+// Flatten nested lists recursively
+func flatten(list any) ([]any, error) {
+	elements, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	flattened := []any{}
+	for i := range elements {
+		if !isList(elements[i]) {
+			flattened = append(flattened, toInterface(elements[i]))
+			continue
+		}
+		nested, err := flatten(elements[i].Interface())
+		if err != nil {
+			return nil, err
+		}
+		flattened = append(flattened, nested...)
+	}
+	return flattened, nil
+}
+
+// Sort list of strings in lexicographical order. Elements are converted to string before sorting
+func sortList(list any) ([]string, error) {
+	elements, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	sorted := make([]string, len(elements))
+	for i := range elements {
+		if !elements[i].IsValid() {
+			return nil, errors.New("given list element must not be null")
+		}
+		sorted[i] = value.ToString(elements[i])
+	}
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+func reverse(list any) ([]any, error) {
+	elements, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	reversed := make([]any, len(elements))
+	for i := range elements {
+		reversed[len(elements)-1-i] = toInterface(elements[i])
+	}
+	return reversed, nil
+}
+
+// Extract elements from start index (inclusive) to end index (exclusive)
+func slice(list any, start, end int) ([]any, error) {
+	elements, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case start < 0:
+		return nil, errors.New("start index must not be less than zero")
+	case end > len(elements):
+		return nil, errors.New("end index must not be greater than the length of the list")
+	case start > end:
+		return nil, errors.New("start index must not be greater than end index")
+	}
+	sliced := make([]any, 0, end-start)
+	for i := start; i < end; i++ {
+		sliced = append(sliced, toInterface(elements[i]))
+	}
+	return sliced, nil
+}
+
+// Construct a map from list of keys and list of values
+func zipmap(keys []string, list any) (map[string]any, error) {
+	elements, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) != len(elements) {
+		return nil, errors.Errorf(
+			"number of keys (%d) does not match number of values (%d)",
+			len(keys), len(elements),
+		)
+	}
+	zipped := make(map[string]any, len(keys))
+	for i := range keys {
+		zipped[keys[i]] = toInterface(elements[i])
+	}
+	return zipped, nil
+}
+
+// Get element by index, index wraps around the length of list
+func element(list any, index int) (any, error) {
+	elements, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(elements) == 0:
+		return nil, errors.New("cannot use element function with an empty list")
+	case index < 0:
+		return nil, errors.New("cannot use element function with a negative index")
+	}
+	return toInterface(elements[index%len(elements)]), nil
+}
+
+// Find the index of the first element which equals to the value
+func index(list, v any) (int, error) {
+	elements, err := toList(list)
+	if err != nil {
+		return 0, err
+	}
+	found := indexOf(elements, reflect.ValueOf(v))
+	if found < 0 {
+		return 0, errors.New("item not found")
+	}
+	return found, nil
+}
+
+// Get the first value which is neither null nor empty string
+func coalesce(args ...any) (any, error) {
+	for i := range args {
+		rv := indirect(reflect.ValueOf(args[i]))
+		if !rv.IsValid() || (rv.Kind() == reflect.String && rv.String() == "") {
+			continue
+		}
+		return rv.Interface(), nil
+	}
+	return nil, errors.New("no non-null, non-empty-string arguments")
+}
+
+// Get the first list which is not empty
+func coalescelist(lists ...any) (any, error) {
+	for i := range lists {
+		elements, err := toList(lists[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %d", i+1)
+		}
+		if len(elements) > 0 {
+			return lists[i], nil
+		}
+	}
+	return nil, errors.New("no non-null arguments")
+}
+
+// Remove null and empty string elements from list
+func compact(list any) ([]string, error) {
+	elements, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	compacted := []string{}
+	for i := range elements {
+		if !elements[i].IsValid() {
+			continue
+		}
+		if s := value.ToString(elements[i]); s != "" {
+			compacted = append(compacted, s)
+		}
+	}
+	return compacted, nil
+}
+
+// Generate list of numbers like Terraform range function:
+//
+// range(limit)
+// range(start, limit)
+// range(start, limit, step)
+func rangeList(params ...float64) ([]any, error) {
+	var start, limit, step float64
+	switch len(params) {
+	case 1:
+		start, limit, step = 0, params[0], 1
+	case 2:
+		start, limit, step = params[0], params[1], 1
+		if limit < start {
+			step = -1
+		}
+	case 3:
+		start, limit, step = params[0], params[1], params[2]
+	default:
+		return nil, errors.New("must have one, two, or three arguments")
+	}
+
+	switch {
+	case step == 0:
+		return nil, errors.New("step must not be zero")
+	case step > 0 && limit < start:
+		return nil, errors.New("for a positive step, start must be less than or equal to limit")
+	case step < 0 && limit > start:
+		return nil, errors.New("for a negative step, start must be greater than or equal to limit")
+	}
+
+	numbers := []any{}
+	for n := start; (step > 0 && n < limit) || (step < 0 && n > limit); n += step {
+		if len(numbers) >= maxRangeLength {
+			return nil, errors.Errorf(
+				"more than %d values were generated; either decrease the difference between start and limit or use a smaller step",
+				maxRangeLength,
+			)
+		}
+		numbers = append(numbers, number(n))
+	}
+	return numbers, nil
+}
+
+// Split list into fixed-size chunks. Size zero produces single chunk which contains all elements
+func chunklist(list any, size int) ([][]any, error) {
+	elements, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	if size < 0 {
+		return nil, errors.New("the size argument must be positive")
+	}
+	if size == 0 {
+		size = len(elements)
+	}
+
+	chunks := [][]any{}
+	for i := 0; i < len(elements); i += size {
+		end := i + size
+		if end > len(elements) {
+			end = len(elements)
+		}
+		chunk := make([]any, 0, end-i)
+		for j := i; j < end; j++ {
+			chunk = append(chunk, toInterface(elements[j]))
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// Get the set which contains all elements of given sets
+func setunion(sets ...any) ([]any, error) {
+	var all []reflect.Value
+	for i := range sets {
+		elements, err := toList(sets[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %d", i+1)
+		}
+		all = append(all, elements...)
+	}
+	return uniqueElements(all), nil
+}
+
+// Get the set which contains elements that all of given sets have
+func setintersection(first any, others ...any) ([]any, error) {
+	elements, err := toList(first)
+	if err != nil {
+		return nil, errors.Wrap(err, "argument 1")
+	}
+	for i := range others {
+		other, err := toList(others[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %d", i+2)
+		}
+		elements = filterElements(elements, other, true)
+	}
+	return uniqueElements(elements), nil
+}
+
+// Get the set which contains elements of the first set that the second set does not have
+func setsubtract(a, b any) ([]any, error) {
+	elements, err := toList(a)
+	if err != nil {
+		return nil, errors.Wrap(err, "argument 1")
+	}
+	other, err := toList(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "argument 2")
+	}
+	return uniqueElements(filterElements(elements, other, false)), nil
+}
+
+func alltrue(list []bool) bool {
+	for i := range list {
+		if !list[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func anytrue(list []bool) bool {
+	for i := range list {
+		if list[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// Sum numbers in list
+func sum(list []float64) (any, error) {
+	if len(list) == 0 {
+		return nil, errors.New("cannot sum an empty list")
+	}
+	var total float64
+	for i := range list {
+		total += list[i]
+	}
+	return number(total), nil
+}
+
+// Represent integral float as int64 so that number is rendered without fraction
+func number(f float64) any {
+	if f == math.Trunc(f) && math.Abs(f) < math.MaxInt64 {
+		return int64(f)
+	}
+	return f
+}
+
+func indexOf(elements []reflect.Value, v reflect.Value) int {
+	key := hashKey(v)
+	for i := range elements {
+		if hashKey(elements[i]) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func uniqueElements(elements []reflect.Value) []any {
+	seen := make(map[string]struct{}, len(elements))
+	unique := []any{}
+	for i := range elements {
+		key := hashKey(elements[i])
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		unique = append(unique, toInterface(elements[i]))
+	}
+	return unique
+}
+
+// Filter elements by whether the element is included in other list or not
+func filterElements(elements, other []reflect.Value, included bool) []reflect.Value {
+	keys := make(map[string]struct{}, len(other))
+	for i := range other {
+		keys[hashKey(other[i])] = struct{}{}
+	}
+	filtered := []reflect.Value{}
+	for i := range elements {
+		if _, ok := keys[hashKey(elements[i])]; ok == included {
+			filtered = append(filtered, elements[i])
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,105 @@
+package stdlib
+
+import (
+	"testing"
+
+	"github.com/ysugimoto/tender"
+)
+
+func TestCollections(t *testing.T) {
+	type Server struct {
+		Name string
+		Port int
+		host string
+	}
+
+	vars := tender.Variables{
+		"list":   []string{"b", "a", "c", "a"},
+		"nums":   []int{3, 1, 2},
+		"empty":  []string{},
+		"nested": []any{[]any{"a", []string{"b"}}, "c"},
+		"mixed":  []any{"", "a", nil, "b"},
+		"obj":    map[string]any{"b": 2, "a": 1},
+		"other":  map[string]int{"b": 20, "c": 30},
+		"server": &Server{Name: "web", Port: 80, host: "localhost"},
+		"bools":  []bool{true, false},
+		"more":   []int{10},
+		"extra":  []string{"d", "a"},
+	}
+	functions := Merge(Strings(), Collections())
+
+	assertRender(t, functions, vars, []renderTest{
+		{name: "length of list", input: `${length(list)}`, expect: "4"},
+		{name: "length of map", input: `${length(obj)}`, expect: "2"},
+		{name: "length of struct", input: `${length(server)}`, expect: "2"},
+		{name: "length of string", input: `${length("🤔🤷")}`, expect: "2"},
+		{name: "length of number", input: `${length(1)}`, isError: true},
+		{name: "keys", input: `${join(",", keys(obj))}`, expect: "a,b"},
+		{name: "keys of struct", input: `${join(",", keys(server))}`, expect: "Name,Port"},
+		{name: "values", input: `${join(",", values(obj))}`, expect: "1,2"},
+		{name: "lookup", input: `${lookup(obj, "a")}`, expect: "1"},
+		{name: "lookup struct field", input: `${lookup(server, "Name")}`, expect: "web"},
+		{name: "lookup default", input: `${lookup(obj, "z", "none")}`, expect: "none"},
+		{name: "lookup missing", input: `${lookup(obj, "z")}`, isError: true},
+		{name: "merge", input: `${join(",", values(merge(obj, other)))}`, expect: "1,20,30"},
+		{name: "merge not map", input: `${merge(obj, list)}`, isError: true},
+		{name: "concat", input: `${join(",", concat(list, nums))}`, expect: "b,a,c,a,3,1,2"},
+		{name: "contains", input: `${contains(list, "a")}`, expect: "true"},
+		{name: "contains not found", input: `${contains(list, "z")}`, expect: "false"},
+		{name: "contains compares type", input: `${contains(nums, "1")}`, expect: "false"},
+		{name: "contains number", input: `${contains(nums, 1.0)}`, expect: "true"},
+		{name: "distinct", input: `${join(",", distinct(list))}`, expect: "b,a,c"},
+		{name: "flatten", input: `${join(",", flatten(nested))}`, expect: "a,b,c"},
+		{name: "sort", input: `${join(",", sort(list))}`, expect: "a,a,b,c"},
+		{name: "sort numbers lexicographically", input: `${join(",", sort(concat(nums, more)))}`, expect: "1,10,2,3"},
+		{name: "reverse", input: `${join(",", reverse(nums))}`, expect: "2,1,3"},
+		{name: "slice", input: `${join(",", slice(list, 1, 3))}`, expect: "a,c"},
+		{name: "slice out of range", input: `${slice(list, 1, 5)}`, isError: true},
+		{name: "zipmap", input: `${lookup(zipmap(list, concat(nums, more)), "c")}`, expect: "2"},
+		{name: "zipmap mismatch", input: `${zipmap(list, nums)}`, isError: true},
+		{name: "element", input: `${element(list, 5)}`, expect: "a"},
+		{name: "element empty list", input: `${element(empty, 0)}`, isError: true},
+		{name: "index", input: `${index(list, "c")}`, expect: "2"},
+		{name: "index not found", input: `${index(list, "z")}`, isError: true},
+		{name: "coalesce", input: `${coalesce("", "a", "b")}`, expect: "a"},
+		{name: "coalesce no value", input: `${coalesce("", "")}`, isError: true},
+		{name: "coalescelist", input: `${join(",", coalescelist(empty, list))}`, expect: "b,a,c,a"},
+		{name: "compact", input: `${join(",", compact(mixed))}`, expect: "a,b"},
+		{name: "range limit", input: `${join(",", range(3))}`, expect: "0,1,2"},
+		{name: "range start and limit", input: `${join(",", range(3, 0))}`, expect: "3,2,1"},
+		{name: "range step", input: `${join(",", range(0, 1, 0.25))}`, expect: "0,0.25,0.5,0.75"},
+		{name: "range zero step", input: `${range(0, 1, 0)}`, isError: true},
+		{name: "range too many", input: `${range(2000)}`, isError: true},
+		{name: "chunklist", input: `${length(chunklist(list, 3))}`, expect: "2"},
+		{name: "chunklist size zero", input: `${length(chunklist(list, 0))}`, expect: "1"},
+		{name: "setunion", input: `${join(",", setunion(list, extra))}`, expect: "b,a,c,d"},
+		{name: "setintersection", input: `${join(",", setintersection(list, keys(obj)))}`, expect: "b,a"},
+		{name: "setsubtract", input: `${join(",", setsubtract(list, keys(obj)))}`, expect: "c"},
+		{name: "alltrue", input: `${alltrue(bools)}`, expect: "false"},
+		{name: "anytrue", input: `${anytrue(bools)}`, expect: "true"},
+		{name: "sum", input: `${sum(nums)}`, expect: "6"},
+		{name: "sum of empty", input: `${sum(empty)}`, isError: true},
+	})
+}
+
+func TestChunklist(t *testing.T) {
+	chunks, err := chunklist([]string{"a", "b", "c"}, 2)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if len(chunks) != 2 || len(chunks[0]) != 2 || len(chunks[1]) != 1 {
+		t.Errorf("Unexpected chunks: %v", chunks)
+	}
+}
+
+func TestSetunion(t *testing.T) {
+	union, err := setunion([]string{"a", "b"}, []any{"b", "c", 1}, []int{1})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if len(union) != 4 {
+		t.Errorf("Unexpected union: %v", union)
+	}
+}
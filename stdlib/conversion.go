@@ -0,0 +1,206 @@
+package stdlib
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender/value"
+)
+
+// Conversions returns Terraform compatible type conversion functions.
+// Conversions are explicit and strict like Terraform, for example tonumber() accepts only numeric string,
+// so template can bridge string values like environment variables and typed Go values.
+func Conversions() map[string]any {
+	return map[string]any{
+		"tostring": tostring,
+		"tonumber": tonumber,
+		"tobool":   tobool,
+		"tolist":   tolist,
+		"toset":    toset,
+		"tomap":    tomap,
+		"type":     typeOf,
+	}
+}
+
+// Convert primitive value to string
+func tostring(v any) (string, error) {
+	rv := indirect(reflect.ValueOf(v))
+	switch {
+	case !rv.IsValid():
+		return "", errors.New("cannot convert null to string")
+	case rv.Kind() == reflect.String, rv.Kind() == reflect.Bool, value.IsNumeric(rv):
+		return value.ToString(rv), nil
+	default:
+		return "", errors.Errorf("cannot convert %s to string", typeOfValue(rv))
+	}
+}
+
+// Convert numeric string or number to number.
+// Integral number is represented as int64, otherwise float64
+func tonumber(v any) (any, error) {
+	rv := indirect(reflect.ValueOf(v))
+	switch {
+	case !rv.IsValid():
+		return nil, errors.New("cannot convert null to number")
+	case value.IsNumeric(rv):
+		f, err := value.Convert(rv, floatType)
+		if err != nil {
+			return nil, err
+		}
+		return number(f.Float()), nil
+	case rv.Kind() == reflect.String:
+		if i, err := strconv.ParseInt(rv.String(), 10, 64); err == nil {
+			return i, nil
+		}
+		// ParseFloat accepts "NaN" and "Inf" but they are not a number in Terraform
+		f, err := strconv.ParseFloat(rv.String(), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, errors.Errorf(`cannot convert "%s" to number; given string must be a decimal representation of a number`, rv.String())
+		}
+		return number(f), nil
+	default:
+		return nil, errors.Errorf("cannot convert %s to number", typeOfValue(rv))
+	}
+}
+
+// Convert "true" or "false" string or bool to bool
+func tobool(v any) (bool, error) {
+	rv := indirect(reflect.ValueOf(v))
+	switch {
+	case !rv.IsValid():
+		return false, errors.New("cannot convert null to bool")
+	case rv.Kind() == reflect.Bool:
+		return rv.Bool(), nil
+	case rv.Kind() == reflect.String:
+		switch rv.String() {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return false, errors.Errorf(`cannot convert "%s" to bool; only the strings "true" or "false" are allowed`, rv.String())
+	default:
+		return false, errors.Errorf("cannot convert %s to bool", typeOfValue(rv))
+	}
+}
+
+// Convert list-like value to list
+func tolist(v any) ([]any, error) {
+	elements, err := toList(v)
+	if err != nil {
+		return nil, errors.Errorf("cannot convert %s to list", typeOfValue(reflect.ValueOf(v)))
+	}
+	list := make([]any, len(elements))
+	for i := range elements {
+		list[i] = toInterface(elements[i])
+	}
+	return list, nil
+}
+
+// Convert list-like value to set, duplicate elements are removed
+func toset(v any) ([]any, error) {
+	elements, err := toList(v)
+	if err != nil {
+		return nil, errors.Errorf("cannot convert %s to set", typeOfValue(reflect.ValueOf(v)))
+	}
+	return uniqueElements(elements), nil
+}
+
+// Convert map or struct to map
+func tomap(v any) (map[string]any, error) {
+	keys, elements, err := toObject(v)
+	if err != nil {
+		return nil, errors.Errorf("cannot convert %s to map", typeOfValue(reflect.ValueOf(v)))
+	}
+	m := make(map[string]any, len(keys))
+	for _, key := range keys {
+		m[key] = toInterface(elements[key])
+	}
+	return m, nil
+}
+
+// Get type name of the value with Terraform type constraint syntax like "list(string)"
+func typeOf(v any) string {
+	return typeOfValue(reflect.ValueOf(v))
+}
+
+func typeOfValue(v reflect.Value) string {
+	v = indirect(v)
+	if !v.IsValid() {
+		return "null"
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		// Slice of interface may have different element types, treat as tuple
+		if v.Type().Elem().Kind() != reflect.Interface {
+			return typeOfType(v.Type())
+		}
+		types := make([]string, v.Len())
+		for i := range types {
+			types[i] = typeOfValue(v.Index(i))
+		}
+		return "tuple([" + strings.Join(types, ", ") + "])"
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.Interface {
+			return typeOfType(v.Type())
+		}
+		keys, elements, _ := toObject(v.Interface()) // nolint:errcheck
+		attributes := make([]string, len(keys))
+		for i, key := range keys {
+			attributes[i] = key + "=" + typeOfValue(elements[key])
+		}
+		return "object({" + strings.Join(attributes, ", ") + "})"
+	default:
+		return typeOfType(v.Type())
+	}
+}
+
+func typeOfType(t reflect.Type) string {
+	return typeOfTypeOnce(t, map[reflect.Type]bool{})
+}
+
+// Self-referential struct like linked list node is expanded only once on the path,
+// the repeated one is represented as bare "object" to avoid infinite recursion
+func typeOfTypeOnce(t reflect.Type, visiting map[reflect.Type]bool) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8,
+		reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "list(" + typeOfTypeOnce(t.Elem(), visiting) + ")"
+	case reflect.Map:
+		return "map(" + typeOfTypeOnce(t.Elem(), visiting) + ")"
+	case reflect.Struct:
+		if visiting[t] {
+			return "object"
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		var attributes []string
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.IsExported() {
+				attributes = append(attributes, f.Name+"="+typeOfTypeOnce(f.Type, visiting))
+			}
+		}
+		sort.Strings(attributes)
+		return "object({" + strings.Join(attributes, ", ") + "})"
+	default:
+		// Interface type could not be determined without value
+		return "any"
+	}
+}
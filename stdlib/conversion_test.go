@@ -0,0 +1,84 @@
+package stdlib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ysugimoto/tender"
+)
+
+func TestConversions(t *testing.T) {
+	type Server struct {
+		Name  string
+		Ports []int
+		host  string
+	}
+	type Node struct {
+		Name     string
+		Next     *Node
+		Children []Node
+	}
+	type Pair struct {
+		Left  Server
+		Right Server
+	}
+
+	t.Setenv("TENDER_PORT", "3")
+	vars := tender.Variables{
+		"port":   3,
+		"ratio":  1.5,
+		"enable": "true",
+		"list":   []string{"a", "b", "a"},
+		"tuple":  []any{"a", 1, true},
+		"obj":    map[string]any{"a": "b", "c": []int{1}},
+		"typed":  map[string]int{"a": 1},
+		"server": Server{Name: "web", Ports: []int{80}},
+		"node":   &Node{Name: "a", Next: &Node{Name: "b"}},
+		"pair":   Pair{},
+	}
+	functions := Merge(Strings(), Collections(), Conversions())
+
+	assertRender(t, functions, vars, []renderTest{
+		{name: "tostring number", input: `${tostring(port) == "3"}`, expect: "true"},
+		{name: "tostring bool", input: `${tostring(true)}`, expect: "true"},
+		{name: "tostring list", input: `${tostring(list)}`, isError: true},
+		{name: "tonumber environment variable", input: `${tonumber(TENDER_PORT) == port}`, expect: "true"},
+		{name: "tonumber float string", input: `${tonumber("1.5") + 1}`, expect: "2.5"},
+		{name: "tonumber number", input: `${tonumber(ratio)}`, expect: "1.5"},
+		{name: "tonumber invalid string", input: `${tonumber("abc")}`, isError: true},
+		{name: "tonumber NaN", input: `${tonumber("NaN")}`, isError: true},
+		{name: "tonumber infinity", input: `${tonumber("inf")}`, isError: true},
+		{name: "tonumber negative infinity", input: `${tonumber("-Infinity")}`, isError: true},
+		{name: "tonumber bool", input: `${tonumber(true)}`, isError: true},
+		{name: "tobool", input: `${tobool(enable) ? "on" : "off"}`, expect: "on"},
+		{name: "tobool false", input: `${tobool("false")}`, expect: "false"},
+		{name: "tobool invalid string", input: `${tobool("yes")}`, isError: true},
+		{name: "tobool number", input: `${tobool(1)}`, isError: true},
+		{name: "tolist", input: `${join(",", tolist(list))}`, expect: "a,b,a"},
+		{name: "tolist map", input: `${tolist(obj)}`, isError: true},
+		{name: "toset", input: `${join(",", toset(list))}`, expect: "a,b"},
+		{name: "toset string", input: `${toset("a")}`, isError: true},
+		{name: "tomap", input: `${lookup(tomap(server), "Name")}`, expect: "web"},
+		{name: "tomap list", input: `${tomap(list)}`, isError: true},
+		{name: "type string", input: `${type("a")}`, expect: "string"},
+		{name: "type number", input: `${type(ratio)}`, expect: "number"},
+		{name: "type list", input: `${type(list)}`, expect: "list(string)"},
+		{name: "type tuple", input: `${type(tuple)}`, expect: "tuple([string, number, bool])"},
+		{name: "type object", input: `${type(obj)}`, expect: "object({a=string, c=list(number)})"},
+		{name: "type map", input: `${type(typed)}`, expect: "map(number)"},
+		{name: "type struct", input: `${type(server)}`, expect: "object({Name=string, Ports=list(number)})"},
+		{name: "type self-referential struct", input: `${type(node)}`, expect: "object({Children=list(object), Name=string, Next=object})"},
+		{name: "type repeated struct", input: `${type(pair)}`, expect: "object({Left=object({Name=string, Ports=list(number)}), Right=object({Name=string, Ports=list(number)})})"},
+	})
+}
+
+func TestConversionErrorPosition(t *testing.T) {
+	_, err := tender.Render("line1\n${tonumber(\"abc\")}", nil, tender.WithFunctions(Conversions()))
+	if err == nil {
+		t.Errorf("Expects error but got nil")
+		return
+	}
+	if !strings.Contains(err.Error(), "at line 2, position 3") {
+		t.Errorf("Error should point to the function position, got %s", err.Error())
+	}
+}
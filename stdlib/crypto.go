@@ -0,0 +1,93 @@
+package stdlib
+
+import (
+	"crypto/md5"  // nolint:gosec
+	"crypto/sha1" // nolint:gosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender/value"
+)
+
+// Well-known namespaces for UUID version 5 defined in RFC 4122
+var uuidNamespaces = map[string]string{
+	"dns":  "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+	"url":  "6ba7b811-9dad-11d1-80b4-00c04fd430c8",
+	"oid":  "6ba7b812-9dad-11d1-80b4-00c04fd430c8",
+	"x500": "6ba7b814-9dad-11d1-80b4-00c04fd430c8",
+}
+
+// Crypto returns Terraform compatible hash functions.
+// All functions are deterministic, they never generate random values.
+// Arguments are stringified in the same way as interporation before hashing.
+func Crypto() map[string]any {
+	return map[string]any{
+		"md5":          hexHash(md5.New),
+		"sha1":         hexHash(sha1.New),
+		"sha256":       hexHash(sha256.New),
+		"sha512":       hexHash(sha512.New),
+		"base64sha256": base64Hash(sha256.New),
+		"base64sha512": base64Hash(sha512.New),
+		"uuidv5":       uuidv5,
+	}
+}
+
+// Compute hash of stringified value
+func digest(h func() hash.Hash, v any) ([]byte, error) {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil, errors.New("argument must not be null")
+	}
+	d := h()
+	d.Write([]byte(value.ToString(rv))) // nolint:errcheck
+	return d.Sum(nil), nil
+}
+
+func hexHash(h func() hash.Hash) func(v any) (string, error) {
+	return func(v any) (string, error) {
+		d, err := digest(h, v)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(d), nil
+	}
+}
+
+func base64Hash(h func() hash.Hash) func(v any) (string, error) {
+	return func(v any) (string, error) {
+		d, err := digest(h, v)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(d), nil
+	}
+}
+
+// Generate name-based UUID version 5.
+// Namespace accepts well-known names "dns", "url", "oid", "x500" or any UUID string
+func uuidv5(namespace, name string) (string, error) {
+	ns, ok := uuidNamespaces[namespace]
+	if !ok {
+		ns = namespace
+	}
+	nsBytes, err := hex.DecodeString(strings.ReplaceAll(ns, "-", ""))
+	if err != nil || len(nsBytes) != 16 {
+		return "", errors.Errorf("uuidv5() doesn't support namespace %s", namespace)
+	}
+
+	d := sha1.New()       // nolint:gosec
+	d.Write(nsBytes)      // nolint:errcheck
+	d.Write([]byte(name)) // nolint:errcheck
+	u := d.Sum(nil)[:16]
+
+	u[6] = (u[6] & 0x0f) | 0x50 // version 5
+	u[8] = (u[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]), nil
+}
@@ -0,0 +1,42 @@
+package stdlib
+
+import (
+	"testing"
+
+	"github.com/ysugimoto/tender"
+)
+
+func TestCrypto(t *testing.T) {
+	vars := tender.Variables{
+		"text":   "hello world",
+		"number": 12,
+		"list":   []int{1, 2},
+	}
+
+	assertRender(t, Crypto(), vars, []renderTest{
+		{name: "md5", input: `${md5(text)}`, expect: "5eb63bbbe01eeed093cb22bb8f5acdc3"},
+		{name: "md5 of number", input: `${md5(number)}`, expect: "c20ad4d76fe97759aa27a0c99bff6710"},
+		{name: "sha1", input: `${sha1(text)}`, expect: "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed"},
+		{name: "sha256", input: `${sha256(text)}`, expect: "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"},
+		{name: "sha256 of list", input: `${sha256(list)}`, expect: "3a316d6d3226f84c1e46e4447fa8d5fd800bff4a1bc6498152523cd4a602b69b"},
+		{
+			name:   "sha512",
+			input:  `${sha512(text)}`,
+			expect: "309ecc489c12d6eb4cc40f50c902f2b4d0ed77ee511a7c7a9bcd3ca86d4cd86f989dd35bc5ff499670da34255b45b0cfd830e81f605dcf7dc5542e93ae9cd76f",
+		},
+		{name: "base64sha256", input: `${base64sha256(text)}`, expect: "uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek="},
+		{
+			name:   "base64sha512",
+			input:  `${base64sha512(text)}`,
+			expect: "MJ7MSJwS1utMxA9QyQLytNDtd+5RGnx6m808qG1M2G+YndNbxf9JlnDaNCVbRbDP2DDoH2Bdz33FVC6TrpzXbw==",
+		},
+		{name: "uuidv5 dns", input: `${uuidv5("dns", "www.example.com")}`, expect: "2ed6657d-e927-568b-95e1-2665a8aea6a2"},
+		{name: "uuidv5 url", input: `${uuidv5("url", "https://www.terraform.io/")}`, expect: "9db6f67c-dd95-5ea0-aa5b-e70e5c5f7cf5"},
+		{
+			name:   "uuidv5 custom namespace",
+			input:  `${uuidv5("6ba7b810-9dad-11d1-80b4-00c04fd430c8", "www.example.com")}`,
+			expect: "2ed6657d-e927-568b-95e1-2665a8aea6a2",
+		},
+		{name: "uuidv5 invalid namespace", input: `${uuidv5("invalid", "www.example.com")}`, isError: true},
+	})
+}
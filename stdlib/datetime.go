@@ -0,0 +1,196 @@
+package stdlib
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender"
+)
+
+// DateTime returns Terraform compatible date and time functions.
+// Timestamps are represented as RFC 3339 strings, and the current time is read from the rendering clock
+// so that the result can be fixed by tender.WithFixedClock() option.
+func DateTime() map[string]any {
+	return map[string]any{
+		"timestamp":     timestamp,
+		"plantimestamp": plantimestamp,
+		"formatdate":    formatdate,
+		"timeadd":       timeadd,
+		"timecmp":       timecmp,
+	}
+}
+
+// Get the current time in UTC
+func timestamp(ctx context.Context) string {
+	return tender.Now(ctx).UTC().Format(time.RFC3339)
+}
+
+// Get the time when rendering started in UTC, the value is the same during single rendering
+func plantimestamp(ctx context.Context) string {
+	return tender.StartedAt(ctx).UTC().Format(time.RFC3339)
+}
+
+// Add duration like "1h30m" or "-10s" to the timestamp
+func timeadd(ts, duration string) (string, error) {
+	t, err := parseTimestamp(ts)
+	if err != nil {
+		return "", err
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid duration")
+	}
+	return t.Add(d).Format(time.RFC3339), nil
+}
+
+// Compare timestamps, returns -1 if a is before b, 1 if a is after b, otherwise 0
+func timecmp(a, b string) (int, error) {
+	ta, err := parseTimestamp(a)
+	if err != nil {
+		return 0, err
+	}
+	tb, err := parseTimestamp(b)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case ta.Before(tb):
+		return -1, nil
+	case ta.After(tb):
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func parseTimestamp(ts string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return t, errors.Errorf("not a valid RFC3339 timestamp: %s", ts)
+	}
+	return t, nil
+}
+
+// Format timestamp by Terraform's date format specification.
+// Sequences of the same letter are format verbs, and text in single quotes is literal:
+//
+// YYYY, YY             - year
+// MMMM, MMM, MM, M     - month name, abbreviated month name, month number
+// DD, D                - day of month
+// EEEE, EEE            - weekday name, abbreviated weekday name
+// hh, h                - hour in 24-hour clock
+// HH, H                - hour in 12-hour clock
+// AA, aa               - AM/PM marker in uppercase or lowercase
+// mm, m                - minute
+// ss, s                - second
+// ZZZZZ, ZZZZ, ZZZ, Z  - timezone offset like "+07:00", "+0700", zone abbreviation, or "Z" for UTC
+func formatdate(spec, ts string) (string, error) {
+	t, err := parseTimestamp(ts)
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(spec); {
+		c := spec[i]
+		switch {
+		case c == '\'':
+			// Quoted literal, and two single quotes represent a literal single quote
+			if i+1 < len(spec) && spec[i+1] == '\'' {
+				buf.WriteByte('\'')
+				i += 2
+				continue
+			}
+			j := i + 1
+			for ; j < len(spec); j++ {
+				if spec[j] != '\'' {
+					buf.WriteByte(spec[j])
+					continue
+				}
+				if j+1 < len(spec) && spec[j+1] == '\'' {
+					buf.WriteByte('\'')
+					j++
+					continue
+				}
+				break
+			}
+			if j >= len(spec) {
+				return "", errors.Errorf("unterminated literal '%s", spec[i+1:])
+			}
+			i = j + 1
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i
+			for j < len(spec) && spec[j] == c {
+				j++
+			}
+			formatted, err := formatDateVerb(spec[i:j], t)
+			if err != nil {
+				return "", err
+			}
+			buf.WriteString(formatted)
+			i = j
+		default:
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	return buf.String(), nil
+}
+
+func formatDateVerb(verb string, t time.Time) (string, error) {
+	switch verb {
+	case "YYYY":
+		return t.Format("2006"), nil
+	case "YY":
+		return t.Format("06"), nil
+	case "MMMM":
+		return t.Format("January"), nil
+	case "MMM":
+		return t.Format("Jan"), nil
+	case "MM":
+		return t.Format("01"), nil
+	case "M":
+		return strconv.Itoa(int(t.Month())), nil
+	case "DD":
+		return t.Format("02"), nil
+	case "D":
+		return strconv.Itoa(t.Day()), nil
+	case "EEEE":
+		return t.Format("Monday"), nil
+	case "EEE":
+		return t.Format("Mon"), nil
+	case "hh":
+		return t.Format("15"), nil
+	case "h":
+		return strconv.Itoa(t.Hour()), nil
+	case "HH":
+		return t.Format("03"), nil
+	case "H":
+		return t.Format("3"), nil
+	case "AA":
+		return t.Format("PM"), nil
+	case "aa":
+		return t.Format("pm"), nil
+	case "mm":
+		return t.Format("04"), nil
+	case "m":
+		return strconv.Itoa(t.Minute()), nil
+	case "ss":
+		return t.Format("05"), nil
+	case "s":
+		return strconv.Itoa(t.Second()), nil
+	case "ZZZZZ":
+		return t.Format("-07:00"), nil
+	case "ZZZZ":
+		return t.Format("-0700"), nil
+	case "ZZZ":
+		return t.Format("MST"), nil
+	case "Z":
+		return t.Format("Z07:00"), nil
+	default:
+		return "", errors.Errorf("invalid date format verb %q", verb)
+	}
+}
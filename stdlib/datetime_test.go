@@ -0,0 +1,70 @@
+package stdlib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/tender"
+)
+
+func TestDateTime(t *testing.T) {
+	vars := tender.Variables{
+		"ts":     "2018-01-02T23:12:01Z",
+		"offset": "2018-01-02T08:05:09+09:00",
+	}
+
+	assertRender(t, DateTime(), vars, []renderTest{
+		{name: "formatdate", input: `${formatdate("DD MMM YYYY hh:mm ZZZ", ts)}`, expect: "02 Jan 2018 23:12 UTC"},
+		{name: "formatdate weekday", input: `${formatdate("EEEE, DD-MMM-YY hh:mm:ss ZZZ", ts)}`, expect: "Tuesday, 02-Jan-18 23:12:01 UTC"},
+		{name: "formatdate abbreviated weekday", input: `${formatdate("EEE, MMMM D", ts)}`, expect: "Tue, January 2"},
+		{name: "formatdate 12-hour clock", input: `${formatdate("H:mm AA / HH aa", ts)}`, expect: "11:12 PM / 11 pm"},
+		{name: "formatdate without padding", input: `${formatdate("M/D h:m:s", offset)}`, expect: "1/2 8:5:9"},
+		{name: "formatdate offset", input: `${formatdate("Z ZZZZ ZZZZZ", offset)}`, expect: "+09:00 +0900 +09:00"},
+		{name: "formatdate utc offset", input: `${formatdate("Z", ts)}`, expect: "Z"},
+		{name: "formatdate literal", input: `${formatdate("'Date:' YYYY-MM-DD 'o''clock' ''", ts)}`, expect: "Date: 2018-01-02 o'clock '"},
+		{name: "formatdate invalid verb", input: `${formatdate("YYY", ts)}`, isError: true},
+		{name: "formatdate unterminated literal", input: `${formatdate("'abc", ts)}`, isError: true},
+		{name: "formatdate invalid timestamp", input: `${formatdate("YYYY", "2018-01-02")}`, isError: true},
+		{name: "timeadd", input: `${timeadd(ts, "10m")}`, expect: "2018-01-02T23:22:01Z"},
+		{name: "timeadd negative", input: `${timeadd(offset, "-1h30m")}`, expect: "2018-01-02T06:35:09+09:00"},
+		{name: "timeadd invalid duration", input: `${timeadd(ts, "1 day")}`, isError: true},
+		{name: "timecmp before", input: `${timecmp(offset, ts)}`, expect: "-1"},
+		{name: "timecmp after", input: `${timecmp(ts, offset)}`, expect: "1"},
+		{name: "timecmp equal", input: `${timecmp("2018-01-02T09:00:00+09:00", "2018-01-02T00:00:00Z")}`, expect: "0"},
+	})
+}
+
+func TestClockInjection(t *testing.T) {
+	fixed := time.Date(2024, 4, 1, 12, 30, 0, 0, time.FixedZone("JST", 9*60*60))
+	tmpl := `${timestamp()} ${plantimestamp()} ${formatdate("YYYY/MM/DD", timestamp())}`
+
+	ret, err := tender.Render(tmpl, nil, tender.WithFunctions(DateTime()), tender.WithFixedClock(fixed))
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	expect := "2024-04-01T03:30:00Z 2024-04-01T03:30:00Z 2024/04/01"
+	if diff := cmp.Diff(expect, ret); diff != "" {
+		t.Errorf("Rendered result mismatch, diff=%s", diff)
+	}
+}
+
+func TestPlantimestampIsStable(t *testing.T) {
+	var ticks int
+	clock := func() time.Time {
+		ticks++
+		return time.Date(2024, 1, 1, 0, 0, ticks, 0, time.UTC)
+	}
+	tmpl := `${plantimestamp()} ${timestamp()} ${plantimestamp()} ${timestamp()}`
+
+	ret, err := tender.Render(tmpl, nil, tender.WithFunctions(DateTime()), tender.WithClock(clock))
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	expect := "2024-01-01T00:00:01Z 2024-01-01T00:00:02Z 2024-01-01T00:00:01Z 2024-01-01T00:00:03Z"
+	if diff := cmp.Diff(expect, ret); diff != "" {
+		t.Errorf("Rendered result mismatch, diff=%s", diff)
+	}
+}
@@ -0,0 +1,316 @@
+package stdlib
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender/value"
+)
+
+// Encoding returns Terraform compatible encoding functions.
+func Encoding() map[string]any {
+	return map[string]any{
+		"jsonencode":       jsonencode,
+		"jsondecode":       jsondecode,
+		"yamlencode":       yamlencode,
+		"base64encode":     base64encode,
+		"base64decode":     base64decode,
+		"textencodebase64": textencodebase64,
+		"textdecodebase64": textdecodebase64,
+		"urlencode":        url.QueryEscape,
+		"csvdecode":        csvdecode,
+	}
+}
+
+// Encode value to JSON string.
+// Map keys are sorted and "<", ">", "&" characters are escaped as unicode sequences like Terraform
+func jsonencode(v any) (string, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return string(encoded), nil
+}
+
+// Decode JSON string to the value.
+// Integral number is decoded as int64 so that it can be used for arithmetic and indexing
+func jsondecode(s string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("extraneous data after JSON value")
+	}
+	return jsonNumbers(decoded), nil
+}
+
+func jsonNumbers(v any) any {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64() // nolint:errcheck
+		return f
+	case []any:
+		for i := range t {
+			t[i] = jsonNumbers(t[i])
+		}
+	case map[string]any:
+		for k := range t {
+			t[k] = jsonNumbers(t[k])
+		}
+	}
+	return v
+}
+
+// Encode value to YAML string with the same style as Terraform:
+// strings are double-quoted, maps and lists are block style and map keys are sorted
+func yamlencode(v any) (string, error) {
+	lines, err := yamlLines(indirect(reflect.ValueOf(v)))
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+func yamlLines(v reflect.Value) ([]string, error) {
+	switch {
+	case isObject(v):
+		keys, elements, err := toObject(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) == 0 {
+			return []string{"{}"}, nil
+		}
+		var lines []string
+		for _, key := range keys {
+			child, err := yamlLines(elements[key])
+			if err != nil {
+				return nil, err
+			}
+			k := strconv.Quote(key) + ":"
+			switch {
+			case !isYAMLBlock(elements[key]):
+				// Multi-line string is continued with its own indentation
+				lines = append(append(lines, k+" "+child[0]), child[1:]...)
+			case isList(elements[key]):
+				// Block sequence in mapping is not indented
+				lines = append(append(lines, k), child...)
+			default:
+				lines = append(lines, k)
+				for i := range child {
+					lines = append(lines, "  "+child[i])
+				}
+			}
+		}
+		return lines, nil
+	case isList(v):
+		if v.Len() == 0 {
+			return []string{"[]"}, nil
+		}
+		var lines []string
+		for i := 0; i < v.Len(); i++ {
+			child, err := yamlLines(indirect(v.Index(i)))
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, "- "+child[0])
+			for j := 1; j < len(child); j++ {
+				lines = append(lines, "  "+child[j])
+			}
+		}
+		return lines, nil
+	case !v.IsValid():
+		return []string{"null"}, nil
+	case v.Kind() == reflect.String:
+		return yamlString(v.String()), nil
+	case v.Kind() == reflect.Bool, value.IsNumeric(v):
+		return []string{value.ToString(v)}, nil
+	default:
+		return nil, errors.Errorf("unsupported value %s for YAML encoding", typeName(v))
+	}
+}
+
+// Non-empty map and list are represented as block
+func isYAMLBlock(v reflect.Value) bool {
+	switch {
+	case isList(v):
+		return indirect(v).Len() > 0
+	case isObject(v):
+		keys, _, _ := toObject(v.Interface()) // nolint:errcheck
+		return len(keys) > 0
+	}
+	return false
+}
+
+// Multi-line string is represented as literal block scalar, otherwise double-quoted string
+func yamlString(s string) []string {
+	if !strings.Contains(s, "\n") || strings.HasPrefix(s, " ") || strings.ContainsFunc(s, func(r rune) bool {
+		return r != '\n' && !unicode.IsPrint(r)
+	}) {
+		return []string{strconv.Quote(s)}
+	}
+
+	// Chomping indicator keeps the trailing newlines as it is
+	header := "|-"
+	trimmed := strings.TrimRight(s, "\n")
+	switch len(s) - len(trimmed) {
+	case 0:
+	case 1:
+		header = "|"
+	default:
+		header = "|+"
+	}
+	if header == "|+" {
+		trimmed = s[:len(s)-1]
+	}
+
+	lines := []string{header}
+	for _, line := range strings.Split(trimmed, "\n") {
+		if line == "" {
+			lines = append(lines, "")
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return lines
+}
+
+func base64encode(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func base64decode(s string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode base64 data")
+	}
+	if !utf8.Valid(decoded) {
+		return "", errors.New("the result of decoding the provided string is not valid UTF-8")
+	}
+	return string(decoded), nil
+}
+
+// Encode string with the character encoding, and then encode it by base64.
+// Supported encodings are UTF-8, UTF-16, UTF-16LE, UTF-16BE and ISO-8859-1
+func textencodebase64(s, encoding string) (string, error) {
+	var encoded []byte
+	switch strings.ToUpper(encoding) {
+	case "UTF-8":
+		encoded = []byte(s)
+	case "UTF-16", "UTF-16BE":
+		encoded = encodeUTF16(s, true)
+	case "UTF-16LE":
+		encoded = encodeUTF16(s, false)
+	case "ISO-8859-1":
+		for _, r := range s {
+			if r > 0xFF {
+				return "", errors.Errorf("the given string contains characters that cannot be represented in %s", encoding)
+			}
+			encoded = append(encoded, byte(r))
+		}
+	default:
+		return "", errors.Errorf("%q is not a supported IANA encoding name", encoding)
+	}
+	return base64.StdEncoding.EncodeToString(encoded), nil
+}
+
+// Decode base64 string and then decode it by the character encoding to UTF-8 string
+func textdecodebase64(s, encoding string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode base64 data")
+	}
+	switch strings.ToUpper(encoding) {
+	case "UTF-8":
+		if !utf8.Valid(decoded) {
+			return "", errors.New("the result of decoding the provided string is not valid UTF-8")
+		}
+		return string(decoded), nil
+	case "UTF-16", "UTF-16BE":
+		return decodeUTF16(decoded, true)
+	case "UTF-16LE":
+		return decodeUTF16(decoded, false)
+	case "ISO-8859-1":
+		runes := make([]rune, len(decoded))
+		for i := range decoded {
+			runes[i] = rune(decoded[i])
+		}
+		return string(runes), nil
+	default:
+		return "", errors.Errorf("%q is not a supported IANA encoding name", encoding)
+	}
+}
+
+func encodeUTF16(s string, bigEndian bool) []byte {
+	units := utf16.Encode([]rune(s))
+	encoded := make([]byte, 0, len(units)*2)
+	for _, u := range units {
+		if bigEndian {
+			encoded = append(encoded, byte(u>>8), byte(u))
+		} else {
+			encoded = append(encoded, byte(u), byte(u>>8))
+		}
+	}
+	return encoded
+}
+
+func decodeUTF16(b []byte, bigEndian bool) (string, error) {
+	if len(b)%2 != 0 {
+		return "", errors.New("the decoded data has odd length for UTF-16")
+	}
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(b[i*2])<<8 | uint16(b[i*2+1])
+		} else {
+			units[i] = uint16(b[i*2]) | uint16(b[i*2+1])<<8
+		}
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// Decode CSV string to the list of maps, the first line is used as the header
+func csvdecode(s string) ([]map[string]string, error) {
+	reader := csv.NewReader(bytes.NewBufferString(s))
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("missing header line")
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	rows := []map[string]string{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		row := make(map[string]string, len(header))
+		for i := range header {
+			row[header[i]] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,89 @@
+package stdlib
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/tender"
+)
+
+func TestEncoding(t *testing.T) {
+	vars := tender.Variables{
+		"obj":  map[string]any{"b": []int{1, 2}, "a": "<tag>&", "c": nil},
+		"json": `{"name":"tender","count":3,"ratio":0.5,"tags":["a","b"]}`,
+		"csv":  "name,port\nweb,80\ndb,5432\n",
+		"bad":  "name,port\nweb\n",
+	}
+	functions := Merge(Strings(), Collections(), Encoding())
+
+	assertRender(t, functions, vars, []renderTest{
+		{name: "jsonencode", input: `${jsonencode(obj)}`, expect: `{"a":"\u003ctag\u003e\u0026","b":[1,2],"c":null}`},
+		{name: "jsonencode string", input: `${jsonencode("foo")}`, expect: `"foo"`},
+		{name: "jsondecode", input: `${lookup(jsondecode(json), "name")}`, expect: "tender"},
+		{name: "jsondecode integer", input: `${lookup(jsondecode(json), "count") + 1}`, expect: "4"},
+		{name: "jsondecode float", input: `${lookup(jsondecode(json), "ratio")}`, expect: "0.5"},
+		{name: "jsondecode list", input: `${join(",", lookup(jsondecode(json), "tags"))}`, expect: "a,b"},
+		{name: "jsondecode invalid", input: `${jsondecode("{")}`, isError: true},
+		{name: "jsondecode extra data", input: `${jsondecode("{} {}")}`, isError: true},
+		{name: "base64encode", input: `${base64encode("Hello World")}`, expect: "SGVsbG8gV29ybGQ="},
+		{name: "base64decode", input: `${base64decode("SGVsbG8gV29ybGQ=")}`, expect: "Hello World"},
+		{name: "base64decode invalid", input: `${base64decode("$$$")}`, isError: true},
+		{name: "base64decode not utf-8", input: `${base64decode("/w==")}`, isError: true},
+		{name: "textencodebase64 UTF-16LE", input: `${textencodebase64("Hello World", "UTF-16LE")}`, expect: "SABlAGwAbABvACAAVwBvAHIAbABkAA=="},
+		{name: "textdecodebase64 UTF-16LE", input: `${textdecodebase64("SABlAGwAbABvACAAVwBvAHIAbABkAA==", "UTF-16LE")}`, expect: "Hello World"},
+		{name: "textencodebase64 unsupported", input: `${textencodebase64("a", "Shift_JIS")}`, isError: true},
+		{name: "urlencode", input: `${urlencode("Hello World!")}`, expect: "Hello+World%21"},
+		{name: "csvdecode", input: `${lookup(element(csvdecode(csv), 1), "port")}`, expect: "5432"},
+		{name: "csvdecode wrong number of fields", input: `${csvdecode(bad)}`, isError: true},
+	})
+}
+
+func TestYamlencode(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  any
+		expect string
+	}{
+		{name: "scalar", input: "foo", expect: "\"foo\"\n"},
+		{name: "map", input: map[string]any{"c": "d", "a": "b"}, expect: "\"a\": \"b\"\n\"c\": \"d\"\n"},
+		{
+			name:   "list in map",
+			input:  map[string]any{"foo": []int{1, 2, 3}, "bar": "baz"},
+			expect: "\"bar\": \"baz\"\n\"foo\":\n- 1\n- 2\n- 3\n",
+		},
+		{
+			name:   "map in list",
+			input:  map[string]any{"foo": []any{1, map[string]string{"a": "b", "c": "d"}, 3}, "bar": "baz"},
+			expect: "\"bar\": \"baz\"\n\"foo\":\n- 1\n- \"a\": \"b\"\n  \"c\": \"d\"\n- 3\n",
+		},
+		{
+			name:   "nested map",
+			input:  map[string]any{"a": map[string]any{"b": map[string]bool{"c": true}}},
+			expect: "\"a\":\n  \"b\":\n    \"c\": true\n",
+		},
+		{
+			name:   "empty collections and null",
+			input:  map[string]any{"a": []string{}, "b": map[string]string{}, "c": nil},
+			expect: "\"a\": []\n\"b\": {}\n\"c\": null\n",
+		},
+		{
+			name:   "multi-line string",
+			input:  map[string]string{"a": "foo\nbar\n", "b": "foo\n\nbar"},
+			expect: "\"a\": |\n  foo\n  bar\n\"b\": |-\n  foo\n\n  bar\n",
+		},
+		{name: "escaped string", input: []string{"tab\there", "quote\""}, expect: "- \"tab\\there\"\n- \"quote\\\"\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := yamlencode(tt.input)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, encoded); diff != "" {
+				t.Errorf("Encoded result mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package stdlib
+
+import (
+	"context"
+	"encoding/base64"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender"
+)
+
+// Filesystem returns Terraform compatible filesystem functions.
+// Functions read files only from the filesystem which is provided by tender.WithFileSystem() option,
+// and any function raises an error if the filesystem is not provided.
+// Paths are slash-separated and resolved from the root of the filesystem.
+func Filesystem() map[string]any {
+	return map[string]any{
+		"file":         file,
+		"fileexists":   fileexists,
+		"fileset":      fileset,
+		"filebase64":   filebase64,
+		"templatefile": templatefile,
+		"basename":     path.Base,
+		"dirname":      path.Dir,
+		"abspath":      abspath,
+	}
+}
+
+// Get filesystem from the rendering context
+func fileSystem(ctx context.Context) (fs.FS, error) {
+	fsys := tender.FileSystem(ctx)
+	if fsys == nil {
+		return nil, errors.New("filesystem is not provided, it must be specified by tender.WithFileSystem option")
+	}
+	return fsys, nil
+}
+
+// Convert the path to the valid path for fs.FS.
+// Leading slash is removed and parent directory reference could not go out of the root
+func fsPath(p string) string {
+	cleaned := path.Clean("/" + p)
+	if cleaned == "/" {
+		return "."
+	}
+	return cleaned[1:]
+}
+
+func readFile(ctx context.Context, p string) ([]byte, error) {
+	fsys, err := fileSystem(ctx)
+	if err != nil {
+		return nil, err
+	}
+	content, err := fs.ReadFile(fsys, fsPath(p))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read file %s", p)
+	}
+	return content, nil
+}
+
+// Read file content as string, the content must be valid UTF-8
+func file(ctx context.Context, p string) (string, error) {
+	content, err := readFile(ctx, p)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(content) {
+		return "", errors.Errorf("contents of %s are not valid UTF-8; use the filebase64 function to obtain the Base64 encoded contents", p)
+	}
+	return string(content), nil
+}
+
+// Read file content as base64 encoded string
+func filebase64(ctx context.Context, p string) (string, error) {
+	content, err := readFile(ctx, p)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(content), nil
+}
+
+// Check file exists, it raises an error if the path exists but it is not a regular file
+func fileexists(ctx context.Context, p string) (bool, error) {
+	fsys, err := fileSystem(ctx)
+	if err != nil {
+		return false, err
+	}
+	info, err := fs.Stat(fsys, fsPath(p))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to stat %s", p)
+	}
+	if !info.Mode().IsRegular() {
+		return false, errors.Errorf("%s is not a regular file, but %q", p, info.Mode().Type().String())
+	}
+	return true, nil
+}
+
+// Enumerate regular files under the path which match the pattern.
+// Pattern supports "*", "?", "[...]" for single path segment, and "**" for zero or more directories.
+// Results are relative to the path and sorted in lexicographical order
+func fileset(ctx context.Context, p, pattern string) ([]string, error) {
+	fsys, err := fileSystem(ctx)
+	if err != nil {
+		return nil, err
+	}
+	root := fsPath(p)
+	patterns := strings.Split(path.Clean(pattern), "/")
+	for i := range patterns {
+		if _, err := path.Match(patterns[i], ""); err != nil {
+			return nil, errors.Wrapf(err, "failed to glob pattern %s", pattern)
+		}
+	}
+
+	matches := []string{}
+	err = fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel := strings.TrimPrefix(name, root+"/")
+		if root == "." {
+			rel = name
+		}
+		if matchSegments(patterns, strings.Split(rel, "/")) {
+			matches = append(matches, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to walk %s", p)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// Match path segments with pattern segments, "**" segment matches zero or more segments
+func matchSegments(patterns, segments []string) bool {
+	if len(patterns) == 0 {
+		return len(segments) == 0
+	}
+	if patterns[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(patterns[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(patterns[0], segments[0]); !ok { // nolint:errcheck
+		return false
+	}
+	return matchSegments(patterns[1:], segments[1:])
+}
+
+// Render template file with provided variables.
+// The template can use the same functions as the current rendering, except calling templatefile recursively
+func templatefile(ctx context.Context, p string, vars map[string]any) (string, error) {
+	if _, err := fileSystem(ctx); err != nil {
+		return "", err
+	}
+	return tender.RenderFile(ctx, fsPath(p), vars)
+}
+
+// Get absolute path from the root of the filesystem
+func abspath(p string) string {
+	return path.Clean("/" + p)
+}
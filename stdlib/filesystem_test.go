@@ -0,0 +1,107 @@
+package stdlib
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ysugimoto/tender"
+)
+
+func TestFilesystem(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hello.txt":             {Data: []byte("Hello World")},
+		"binary.dat":            {Data: []byte{0xff, 0xfe}},
+		"templates/greet.tpl":   {Data: []byte(`Hello ${upper(name)}!`)},
+		"templates/recurse.tpl": {Data: []byte(`${templatefile("templates/greet.tpl", inner)}`)},
+		"files/a.txt":           {Data: []byte("a")},
+		"files/b.json":          {Data: []byte("{}")},
+		"files/sub/c.txt":       {Data: []byte("c")},
+		"files/sub/deep/d.txt":  {Data: []byte("d")},
+	}
+	vars := tender.Variables{
+		"vars": map[string]any{
+			"name":  "tender",
+			"inner": map[string]any{"name": "inner"},
+		},
+	}
+	functions := Merge(Strings(), Filesystem())
+
+	tests := []renderTest{
+		{name: "file", input: `${file("hello.txt")}`, expect: "Hello World"},
+		{name: "file with absolute path", input: `${file("/hello.txt")}`, expect: "Hello World"},
+		{name: "file could not escape root", input: `${file("../../hello.txt")}`, expect: "Hello World"},
+		{name: "file not found", input: `${file("missing.txt")}`, isError: true},
+		{name: "file not utf-8", input: `${file("binary.dat")}`, isError: true},
+		{name: "filebase64", input: `${filebase64("binary.dat")}`, expect: "//4="},
+		{name: "fileexists", input: `${fileexists("hello.txt")}`, expect: "true"},
+		{name: "fileexists not found", input: `${fileexists("missing.txt")}`, expect: "false"},
+		{name: "fileexists directory", input: `${fileexists("files")}`, isError: true},
+		{name: "fileset", input: `${join(",", fileset("files", "*.txt"))}`, expect: "a.txt"},
+		{name: "fileset double star", input: `${join(",", fileset("files", "**/*.txt"))}`, expect: "a.txt,sub/c.txt,sub/deep/d.txt"},
+		{name: "fileset sub directory", input: `${join(",", fileset("files", "sub/*"))}`, expect: "sub/c.txt"},
+		{name: "fileset root", input: `${join(",", fileset("/", "*.txt"))}`, expect: "hello.txt"},
+		{name: "fileset invalid pattern", input: `${fileset("files", "[")}`, isError: true},
+		{name: "templatefile", input: `${templatefile("templates/greet.tpl", vars)}`, expect: "Hello TENDER!"},
+		{name: "templatefile undefined variable", input: `${templatefile("templates/greet.tpl", hello)}`, isError: true},
+		{name: "basename", input: `${basename("foo/bar/baz.txt")}`, expect: "baz.txt"},
+		{name: "dirname", input: `${dirname("foo/bar/baz.txt")}`, expect: "foo/bar"},
+		{name: "abspath", input: `${abspath("foo/../bar/./baz.txt")}`, expect: "/bar/baz.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ret, err := tender.Render(tt.input, vars, tender.WithFunctions(functions), tender.WithFileSystem(fsys))
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error but got nil, rendered=%s", ret)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if ret != tt.expect {
+				t.Errorf("Rendered result mismatch, expect=%s, actual=%s", tt.expect, ret)
+			}
+		})
+	}
+}
+
+func TestFilesystemNotProvided(t *testing.T) {
+	_, err := tender.Render(`${file("/etc/passwd")}`, nil, tender.WithFunctions(Filesystem()))
+	if err == nil {
+		t.Errorf("Expects error but got nil")
+		return
+	}
+	if !strings.Contains(err.Error(), "filesystem is not provided") {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
+
+func TestTemplatefileRecursion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"greet.tpl":   {Data: []byte(`Hello ${name}!`)},
+		"recurse.tpl": {Data: []byte(`${templatefile("greet.tpl", inner)}`)},
+	}
+	vars := tender.Variables{
+		"vars": map[string]any{
+			"inner": map[string]any{"name": "inner"},
+		},
+	}
+
+	_, err := tender.Render(
+		`${templatefile("recurse.tpl", vars)}`,
+		vars,
+		tender.WithFunctions(Filesystem()),
+		tender.WithFileSystem(fsys),
+	)
+	if err == nil {
+		t.Errorf("Expects error but got nil")
+		return
+	}
+	if !strings.Contains(err.Error(), "recursively") {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
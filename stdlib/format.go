@@ -0,0 +1,208 @@
+package stdlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender/value"
+)
+
+var (
+	stringType = reflect.TypeOf("")
+	intType    = reflect.TypeOf(int64(0))
+	floatType  = reflect.TypeOf(float64(0))
+	boolType   = reflect.TypeOf(false)
+)
+
+// Produce a string by formatting arguments according to the specification string.
+// Supported verbs are the same as Terraform's format function:
+//
+// %v      - default format of the value, list and map are formatted as JSON
+// %#v     - JSON representation of the value
+// %t      - bool
+// %b, %d, %o, %x, %X - integer in base 2, 10, 8, 16
+// %e, %E, %f, %g, %G - floating point number
+// %s      - string
+// %q      - quoted string as JSON string
+// %%      - literal percent sign
+//
+// Flags, width, precision and explicit argument index like "%[2]s" are also supported.
+func format(spec string, args ...any) (string, error) {
+	var buf bytes.Buffer
+	argIndex := 0
+	usedArgs := 0
+
+	for i := 0; i < len(spec); i++ {
+		if spec[i] != '%' {
+			buf.WriteByte(spec[i])
+			continue
+		}
+		start := i
+		i++
+		if i < len(spec) && spec[i] == '%' {
+			buf.WriteByte('%')
+			continue
+		}
+
+		// Parse flags, explicit argument index, width and precision
+		var verb strings.Builder
+		verb.WriteByte('%')
+		for i < len(spec) && strings.IndexByte("+-# 0", spec[i]) >= 0 {
+			verb.WriteByte(spec[i])
+			i++
+		}
+		if i < len(spec) && spec[i] == '[' {
+			end := strings.IndexByte(spec[i:], ']')
+			if end < 0 {
+				return "", errors.Errorf("unclosed argument index at %d", i)
+			}
+			var n int
+			if _, err := fmt.Sscanf(spec[i+1:i+end], "%d", &n); err != nil || n < 1 {
+				return "", errors.Errorf("invalid argument index %q at %d", spec[i:i+end+1], i)
+			}
+			argIndex = n - 1
+			i += end + 1
+		}
+		for i < len(spec) && (spec[i] >= '0' && spec[i] <= '9' || spec[i] == '.') {
+			verb.WriteByte(spec[i])
+			i++
+		}
+		if i >= len(spec) {
+			return "", errors.Errorf("unterminated format verb at %d", start)
+		}
+		verb.WriteByte(spec[i])
+
+		if argIndex >= len(args) {
+			return "", errors.Errorf(
+				`not enough arguments for "%s" at %d: need index %d but have %d total`,
+				spec[start:i+1], start, argIndex+1, len(args),
+			)
+		}
+		formatted, err := formatValue(verb.String(), reflect.ValueOf(args[argIndex]))
+		if err != nil {
+			return "", errors.Wrapf(err, `unsupported value for "%s" at %d`, spec[start:i+1], start)
+		}
+		buf.WriteString(formatted)
+
+		argIndex++
+		if argIndex > usedArgs {
+			usedArgs = argIndex
+		}
+	}
+
+	if usedArgs < len(args) {
+		return "", errors.Errorf("too many arguments; only %d used by format string", usedArgs)
+	}
+	return buf.String(), nil
+}
+
+// Format single value by Go verb which has the same flags as Terraform verb
+func formatValue(verb string, v reflect.Value) (string, error) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return "", errors.New("null value cannot be formatted")
+	}
+
+	last := verb[len(verb)-1]
+	switch last {
+	case 'v':
+		// "%#v" or collection value is formatted as JSON
+		if strings.Contains(verb, "#") || isList(v) || v.Kind() == reflect.Map || v.Kind() == reflect.Struct {
+			encoded, err := json.Marshal(v.Interface())
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf(stringVerb(verb), string(encoded)), nil
+		}
+		return fmt.Sprintf(stringVerb(verb), value.ToString(v)), nil
+	case 't':
+		b, err := value.Convert(v, boolType)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(verb, b.Bool()), nil
+	case 'b', 'd', 'o', 'x', 'X':
+		n, err := value.Convert(v, intType)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(verb, n.Int()), nil
+	case 'e', 'E', 'f', 'g', 'G':
+		f, err := value.Convert(v, floatType)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(verb, f.Float()), nil
+	case 's':
+		s, err := value.Convert(v, stringType)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(verb, s.String()), nil
+	case 'q':
+		s, err := value.Convert(v, stringType)
+		if err != nil {
+			return "", err
+		}
+		quoted, err := json.Marshal(s.String())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(stringVerb(verb), string(quoted)), nil
+	default:
+		return "", errors.Errorf("unsupported format verb %q", string(last))
+	}
+}
+
+// Replace verb to "s" and drop flags which are not meaningful for string
+func stringVerb(verb string) string {
+	return strings.NewReplacer("#", "", "+", "", " ", "").Replace(verb[:len(verb)-1]) + "s"
+}
+
+// Produce a list of strings by formatting arguments element-wise.
+// List arguments must have the same length, and non-list arguments are repeated for each element.
+func formatlist(spec string, args ...any) ([]string, error) {
+	length := -1
+	lengthArg := 0
+	for i := range args {
+		v := indirect(reflect.ValueOf(args[i]))
+		if !isList(v) {
+			continue
+		}
+		if length >= 0 && v.Len() != length {
+			return nil, errors.Errorf(
+				"argument %d has length %d, which is inconsistent with argument %d of length %d",
+				i+1, v.Len(), lengthArg+1, length,
+			)
+		}
+		length = v.Len()
+		lengthArg = i
+	}
+	// If no list argument is provided, produce single element list
+	if length < 0 {
+		length = 1
+	}
+
+	results := make([]string, length)
+	for n := 0; n < length; n++ {
+		values := make([]any, len(args))
+		for i := range args {
+			v := indirect(reflect.ValueOf(args[i]))
+			if isList(v) {
+				values[i] = v.Index(n).Interface()
+			} else {
+				values[i] = args[i]
+			}
+		}
+		formatted, err := format(spec, values...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error on format iteration %d", n)
+		}
+		results[n] = formatted
+	}
+	return results, nil
+}
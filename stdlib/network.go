@@ -0,0 +1,191 @@
+package stdlib
+
+import (
+	"math/big"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// Maximum bits to extend prefix in one call, the same as Terraform for portability with 32-bit systems
+const maxNewBits = 32
+
+// Network returns Terraform compatible IP network functions which support both IPv4 and IPv6.
+func Network() map[string]any {
+	return map[string]any{
+		"cidrhost":    cidrhost,
+		"cidrnetmask": cidrnetmask,
+		"cidrsubnet":  cidrsubnet,
+		"cidrsubnets": cidrsubnets,
+	}
+}
+
+// Calculate a full host IP address for the given host number within the prefix.
+// Negative host number counts from the end of the range
+func cidrhost(prefix string, hostnum int64) (string, error) {
+	network, err := parseCIDR(prefix)
+	if err != nil {
+		return "", err
+	}
+	parentLen, addrLen := network.Mask.Size()
+	hostLen := addrLen - parentLen
+
+	maxHostNum := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(hostLen)), big.NewInt(1))
+	num := big.NewInt(hostnum)
+	abs := new(big.Int).Set(num)
+	if num.Sign() < 0 {
+		abs.Neg(num)
+		abs.Sub(abs, big.NewInt(1))
+		num.Sub(maxHostNum, abs)
+	}
+	if abs.Cmp(maxHostNum) > 0 {
+		return "", errors.Errorf("prefix of %d does not accommodate a host numbered %d", parentLen, hostnum)
+	}
+	return insertNumIntoIP(network.IP, num, addrLen).String(), nil
+}
+
+// Convert IPv4 prefix to the subnet mask address
+func cidrnetmask(prefix string) (string, error) {
+	network, err := parseCIDR(prefix)
+	if err != nil {
+		return "", err
+	}
+	if len(network.IP) != net.IPv4len {
+		return "", errors.Errorf("IPv6 addresses cannot have a netmask: %s", prefix)
+	}
+	return net.IP(network.Mask).String(), nil
+}
+
+// Calculate a subnet address within the prefix by extending newbits and numbered netnum
+func cidrsubnet(prefix string, newbits, netnum int64) (string, error) {
+	network, err := parseCIDR(prefix)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case newbits < 0:
+		return "", errors.New("newbits must not be negative")
+	case newbits > maxNewBits:
+		return "", errors.Errorf("may not extend prefix by more than %d bits", maxNewBits)
+	case netnum < 0:
+		return "", errors.New("netnum must not be negative")
+	}
+
+	parentLen, addrLen := network.Mask.Size()
+	newPrefixLen := parentLen + int(newbits)
+	if newPrefixLen > addrLen {
+		return "", errors.Errorf("insufficient address space to extend prefix of %d by %d", parentLen, newbits)
+	}
+	if maxNetNum := int64(1)<<uint64(newbits) - 1; netnum > maxNetNum {
+		return "", errors.Errorf("prefix extension of %d does not accommodate a subnet numbered %d", newbits, netnum)
+	}
+
+	subnet := &net.IPNet{
+		IP:   insertNumIntoIP(network.IP, big.NewInt(netnum), newPrefixLen),
+		Mask: net.CIDRMask(newPrefixLen, addrLen),
+	}
+	return subnet.String(), nil
+}
+
+// Calculate a sequence of consecutive subnet addresses which have each newbits extension
+func cidrsubnets(prefix string, newbits ...int64) ([]string, error) {
+	network, err := parseCIDR(prefix)
+	if err != nil {
+		return nil, err
+	}
+	subnets := []string{}
+	if len(newbits) == 0 {
+		return subnets, nil
+	}
+
+	startLen, addrLen := network.Mask.Size()
+	current := previousSubnet(network, startLen+int(newbits[0]))
+	for i, bits := range newbits {
+		switch {
+		case bits < 1:
+			return nil, errors.Errorf("argument %d: must extend prefix by at least one bit", i+2)
+		case bits > maxNewBits:
+			return nil, errors.Errorf("argument %d: may not extend prefix by more than %d bits", i+2, maxNewBits)
+		}
+		length := startLen + int(bits)
+		if length > addrLen {
+			protocol := "IP"
+			switch addrLen {
+			case net.IPv4len * 8:
+				protocol = "IPv4"
+			case net.IPv6len * 8:
+				protocol = "IPv6"
+			}
+			return nil, errors.Errorf(
+				"argument %d: would extend prefix to %d bits, which is too long for an %s address",
+				i+2, length, protocol,
+			)
+		}
+		next, rollover := nextSubnet(current, length)
+		if rollover || !network.Contains(next.IP) {
+			return nil, errors.Errorf(
+				"argument %d: not enough remaining address space for a subnet with a prefix of %d bits after %s",
+				i+2, length, current.String(),
+			)
+		}
+		current = next
+		subnets = append(subnets, current.String())
+	}
+	return subnets, nil
+}
+
+// Parse CIDR notation, IPv4 address is normalized to 4 bytes representation
+func parseCIDR(prefix string) (*net.IPNet, error) {
+	_, network, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid CIDR expression")
+	}
+	if ip := network.IP.To4(); ip != nil && len(network.Mask) == net.IPv4len {
+		network.IP = ip
+	}
+	return network, nil
+}
+
+// Insert number into the host part of IP address which starts from prefixLen bit
+func insertNumIntoIP(ip net.IP, num *big.Int, prefixLen int) net.IP {
+	shifted := new(big.Int).Lsh(num, uint(len(ip)*8-prefixLen))
+	return intToIP(new(big.Int).Or(ipToInt(ip), shifted), len(ip))
+}
+
+func ipToInt(ip net.IP) *big.Int {
+	return new(big.Int).SetBytes(ip)
+}
+
+// Convert integer to IP address, overflowed bits are discarded
+func intToIP(n *big.Int, length int) net.IP {
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(length*8))
+	n = new(big.Int).Mod(n, modulus)
+	ip := make(net.IP, length)
+	n.FillBytes(ip)
+	return ip
+}
+
+// Get the last address of network
+func lastAddress(network *net.IPNet) net.IP {
+	ip := make(net.IP, len(network.IP))
+	for i := range ip {
+		ip[i] = network.IP[i] | ^network.Mask[i]
+	}
+	return ip
+}
+
+// Get the subnet which has prefixLen and is placed just before the network
+func previousSubnet(network *net.IPNet, prefixLen int) *net.IPNet {
+	mask := net.CIDRMask(prefixLen, len(network.IP)*8)
+	previous := intToIP(new(big.Int).Sub(ipToInt(network.IP), big.NewInt(1)), len(network.IP))
+	return &net.IPNet{IP: previous.Mask(mask), Mask: mask}
+}
+
+// Get the subnet which has prefixLen and is placed just after the network.
+// Second return value reports the address space is rolled over
+func nextSubnet(network *net.IPNet, prefixLen int) (*net.IPNet, bool) {
+	mask := net.CIDRMask(prefixLen, len(network.IP)*8)
+	current := &net.IPNet{IP: lastAddress(network).Mask(mask), Mask: mask}
+	next := intToIP(new(big.Int).Add(ipToInt(lastAddress(current)), big.NewInt(1)), len(network.IP))
+	return &net.IPNet{IP: next.Mask(mask), Mask: mask}, ipToInt(next).Sign() == 0
+}
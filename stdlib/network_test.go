@@ -0,0 +1,77 @@
+package stdlib
+
+import (
+	"testing"
+
+	"github.com/ysugimoto/tender"
+)
+
+func TestNetwork(t *testing.T) {
+	functions := Merge(Strings(), Network())
+
+	assertRender(t, functions, tender.Variables{}, []renderTest{
+		{name: "cidrhost", input: `${cidrhost("10.12.112.0/20", 16)}`, expect: "10.12.112.16"},
+		{name: "cidrhost carries", input: `${cidrhost("10.12.112.0/20", 268)}`, expect: "10.12.113.12"},
+		{name: "cidrhost negative", input: `${cidrhost("10.12.112.0/24", -1)}`, expect: "10.12.112.255"},
+		{name: "cidrhost ipv6", input: `${cidrhost("fd00:fd12:3456:7890:00a2::/72", 34)}`, expect: "fd00:fd12:3456:7890::22"},
+		{
+			name:    "cidrhost out of range",
+			input:   `${cidrhost("10.12.112.0/24", 256)}`,
+			isError: true,
+			message: "prefix of 24 does not accommodate a host numbered 256",
+		},
+		{
+			name:    "cidrhost negative out of range",
+			input:   `${cidrhost("10.12.112.0/24", -257)}`,
+			isError: true,
+			message: "prefix of 24 does not accommodate a host numbered -257",
+		},
+		{name: "cidrhost invalid prefix", input: `${cidrhost("10.12.112.0", 1)}`, isError: true},
+		{name: "cidrnetmask", input: `${cidrnetmask("172.16.0.0/12")}`, expect: "255.240.0.0"},
+		{
+			name:    "cidrnetmask ipv6",
+			input:   `${cidrnetmask("fd00::/8")}`,
+			isError: true,
+			message: "IPv6 addresses cannot have a netmask: fd00::/8",
+		},
+		{name: "cidrsubnet", input: `${cidrsubnet("172.16.0.0/12", 4, 2)}`, expect: "172.18.0.0/16"},
+		{name: "cidrsubnet last", input: `${cidrsubnet("10.1.2.0/24", 4, 15)}`, expect: "10.1.2.240/28"},
+		{name: "cidrsubnet ipv6", input: `${cidrsubnet("fd00:fd12:3456:7890::/56", 16, 162)}`, expect: "fd00:fd12:3456:7800:a200::/72"},
+		{name: "cidrsubnet zero extension", input: `${cidrsubnet("10.1.2.0/24", 0, 0)}`, expect: "10.1.2.0/24"},
+		{
+			name:    "cidrsubnet insufficient space",
+			input:   `${cidrsubnet("10.1.2.0/24", 9, 0)}`,
+			isError: true,
+			message: "insufficient address space to extend prefix of 24 by 9",
+		},
+		{
+			name:    "cidrsubnet netnum out of range",
+			input:   `${cidrsubnet("10.1.2.0/24", 4, 16)}`,
+			isError: true,
+			message: "prefix extension of 4 does not accommodate a subnet numbered 16",
+		},
+		{
+			name:   "cidrsubnets",
+			input:  `${join(",", cidrsubnets("10.1.0.0/16", 4, 4, 8, 4))}`,
+			expect: "10.1.0.0/20,10.1.16.0/20,10.1.32.0/24,10.1.48.0/20",
+		},
+		{
+			name:   "cidrsubnets ipv6",
+			input:  `${join(",", cidrsubnets("fd00:fd12:3456:7890::/56", 16, 16, 16, 32))}`,
+			expect: "fd00:fd12:3456:7800::/72,fd00:fd12:3456:7800:100::/72,fd00:fd12:3456:7800:200::/72,fd00:fd12:3456:7800:300::/88",
+		},
+		{name: "cidrsubnets without newbits", input: `${join(",", cidrsubnets("10.1.0.0/16"))}`, expect: ""},
+		{
+			name:    "cidrsubnets not enough space",
+			input:   `${cidrsubnets("10.1.0.0/24", 1, 1, 1)}`,
+			isError: true,
+			message: "argument 4: not enough remaining address space for a subnet with a prefix of 25 bits after 10.1.0.128/25",
+		},
+		{
+			name:    "cidrsubnets too long",
+			input:   `${cidrsubnets("10.1.0.0/24", 9)}`,
+			isError: true,
+			message: "argument 2: would extend prefix to 33 bits, which is too long for an IPv4 address",
+		},
+	})
+}
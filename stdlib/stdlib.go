@@ -0,0 +1,123 @@
+// Package stdlib provides Terraform compatible built-in functions for tender templates.
+//
+// Functions are grouped into sets and all sets are opt-in.
+// Register only the sets you need with tender.WithFunctions() option:
+//
+//	tender.Render(tmpl, vars, tender.WithFunctions(stdlib.Strings()))
+package stdlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/tender/value"
+)
+
+// Merge multiple function sets into one map.
+// Later set overrides the function which has the same name.
+func Merge(sets ...map[string]any) map[string]any {
+	merged := map[string]any{}
+	for i := range sets {
+		for name, fn := range sets[i] {
+			merged[name] = fn
+		}
+	}
+	return merged
+}
+
+// Unwrap interface and pointer value to the underlying concrete value
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// Check value is list-like, string is not treated as list
+func isList(v reflect.Value) bool {
+	v = indirect(v)
+	return v.IsValid() && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array)
+}
+
+// Check value is object-like, map or struct
+func isObject(v reflect.Value) bool {
+	v = indirect(v)
+	return v.IsValid() && (v.Kind() == reflect.Map || v.Kind() == reflect.Struct)
+}
+
+// Get type name for error message
+func typeName(v reflect.Value) string {
+	v = indirect(v)
+	if !v.IsValid() {
+		return "null"
+	}
+	return v.Type().String()
+}
+
+// Convert list-like value to the slice of its elements
+func toList(v any) ([]reflect.Value, error) {
+	rv := indirect(reflect.ValueOf(v))
+	if !isList(rv) {
+		return nil, errors.Errorf("list value is required but got %s", typeName(rv))
+	}
+	elements := make([]reflect.Value, rv.Len())
+	for i := range elements {
+		elements[i] = indirect(rv.Index(i))
+	}
+	return elements, nil
+}
+
+// Convert object-like value to the sorted keys and its elements.
+// Struct is treated as object which has exported fields.
+func toObject(v any) ([]string, map[string]reflect.Value, error) {
+	rv := indirect(reflect.ValueOf(v))
+	if !isObject(rv) {
+		return nil, nil, errors.Errorf("map or object value is required but got %s", typeName(rv))
+	}
+
+	elements := map[string]reflect.Value{}
+	if rv.Kind() == reflect.Map {
+		iter := rv.MapRange()
+		for iter.Next() {
+			elements[value.ToString(iter.Key())] = indirect(iter.Value())
+		}
+	} else {
+		for i := 0; i < rv.NumField(); i++ {
+			if f := rv.Type().Field(i); f.IsExported() {
+				elements[f.Name] = indirect(rv.Field(i))
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(elements))
+	for key := range elements {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, elements, nil
+}
+
+// Get interface value from reflect.Value, invalid value is treated as nil
+func toInterface(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// Produce the key for comparing value equality.
+// Values are compared by JSON representation so that numbers are equal regardless of Go types,
+// and string "1" is not equal to number 1.
+func hashKey(v reflect.Value) string {
+	encoded, err := json.Marshal(toInterface(indirect(v)))
+	if err != nil {
+		return fmt.Sprintf("%#v", toInterface(v))
+	}
+	return string(encoded)
+}
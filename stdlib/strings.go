@@ -0,0 +1,200 @@
+package stdlib
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Strings returns Terraform compatible string functions.
+// See https://developer.hashicorp.com/terraform/language/functions for each function behavior.
+func Strings() map[string]any {
+	return map[string]any{
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      title,
+		"trimspace":  strings.TrimSpace,
+		"trim":       strings.Trim,
+		"trimprefix": strings.TrimPrefix,
+		"trimsuffix": strings.TrimSuffix,
+		"replace":    replace,
+		"split":      split,
+		"join":       join,
+		"format":     format,
+		"formatlist": formatlist,
+		"indent":     indent,
+		"chomp":      chomp,
+		"substr":     substr,
+		"strrev":     strrev,
+		"regex":      regex,
+		"regexall":   regexall,
+	}
+}
+
+// Make the first letter of each word uppercase, the same as deprecated strings.Title
+func title(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		if isSeparator(prev) {
+			prev = r
+			return unicode.ToTitle(r)
+		}
+		prev = r
+		return r
+	}, s)
+}
+
+func isSeparator(r rune) bool {
+	switch {
+	case r <= 0x7F:
+		switch {
+		case '0' <= r && r <= '9', 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', r == '_':
+			return false
+		}
+		return true
+	case unicode.IsLetter(r), unicode.IsDigit(r):
+		return false
+	}
+	return unicode.IsSpace(r)
+}
+
+// Replace substring. If substring is wrapped in forward slashes, it is treated as regular expression
+// and replacement can refer captured groups like "$1"
+func replace(s, substr, replacement string) (string, error) {
+	if len(substr) > 1 && strings.HasPrefix(substr, "/") && strings.HasSuffix(substr, "/") {
+		re, err := regexp.Compile(substr[1 : len(substr)-1])
+		if err != nil {
+			return "", errors.Wrap(err, "invalid regular expression pattern")
+		}
+		return re.ReplaceAllString(s, replacement), nil
+	}
+	return strings.ReplaceAll(s, substr, replacement), nil
+}
+
+func split(separator, s string) []string {
+	return strings.Split(s, separator)
+}
+
+func join(separator string, lists ...[]string) (string, error) {
+	if len(lists) == 0 {
+		return "", errors.New("at least one list is required")
+	}
+	var values []string
+	for i := range lists {
+		values = append(values, lists[i]...)
+	}
+	return strings.Join(values, separator), nil
+}
+
+// Add spaces to the beginning of all but the first line
+func indent(spaces int, s string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+strings.Repeat(" ", spaces))
+}
+
+var trailingNewlines = regexp.MustCompile(`(?:\r\n|\r|\n)+$`)
+
+func chomp(s string) string {
+	return trailingNewlines.ReplaceAllString(s, "")
+}
+
+// Extract substring by character offset and length.
+// Negative offset counts from the end of string, and length -1 means the rest of string
+func substr(s string, offset, length int) (string, error) {
+	if length < -1 {
+		return "", errors.New("length must be non-negative integer or -1")
+	}
+	runes := []rune(s)
+	if offset < 0 {
+		offset += len(runes)
+		if offset < 0 {
+			offset = 0
+		}
+	}
+	if offset > len(runes) {
+		return "", nil
+	}
+	runes = runes[offset:]
+	if length == -1 || length > len(runes) {
+		length = len(runes)
+	}
+	return string(runes[:length]), nil
+}
+
+func strrev(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// Apply regular expression and return the first match.
+// The result type depends on the capture groups in the pattern:
+//
+// no capture groups      -> matched string
+// unnamed capture groups -> list of captured strings
+// named capture groups   -> map of captured strings keyed by the group name
+func regex(pattern, s string) (any, error) {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+	match := re.FindStringSubmatch(s)
+	if match == nil {
+		return nil, errors.New("pattern did not match any part of the given string")
+	}
+	return regexResult(re, match), nil
+}
+
+// Apply regular expression and return all matches as a list.
+// Each element has the same type as the result of regex function
+func regexall(pattern, s string) ([]any, error) {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+	matches := re.FindAllStringSubmatch(s, -1)
+	results := make([]any, len(matches))
+	for i := range matches {
+		results[i] = regexResult(re, matches[i])
+	}
+	return results, nil
+}
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid regular expression pattern")
+	}
+
+	var named, unnamed int
+	for _, name := range re.SubexpNames()[1:] {
+		if name == "" {
+			unnamed++
+		} else {
+			named++
+		}
+	}
+	if named > 0 && unnamed > 0 {
+		return nil, errors.New("invalid regular expression pattern: named and unnamed capture groups must not be mixed")
+	}
+	return re, nil
+}
+
+func regexResult(re *regexp.Regexp, match []string) any {
+	names := re.SubexpNames()
+	switch {
+	case len(names) == 1:
+		return match[0]
+	case names[1] != "":
+		captured := make(map[string]string, len(names)-1)
+		for i := 1; i < len(names); i++ {
+			captured[names[i]] = match[i]
+		}
+		return captured
+	default:
+		return match[1:]
+	}
+}
@@ -0,0 +1,128 @@
+package stdlib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/tender"
+)
+
+type renderTest struct {
+	name    string
+	input   string
+	expect  string
+	isError bool
+	// message is the error message which the function returns, checked only when it is specified
+	message string
+}
+
+func assertRender(t *testing.T, functions map[string]any, vars tender.Variables, tests []renderTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ret, err := tender.Render(tt.input, vars, tender.WithFunctions(functions))
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error but got nil, rendered=%s", ret)
+					return
+				}
+				if tt.message != "" && !strings.Contains(err.Error(), tt.message) {
+					t.Errorf("Error message mismatch, expect=%s, actual=%s", tt.message, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, ret); diff != "" {
+				t.Errorf("Rendered result mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestStrings(t *testing.T) {
+	vars := tender.Variables{
+		"name":     "hello world",
+		"list":     []string{"a", "b", "c"},
+		"multi":    "line1\nline2\nline3",
+		"newlines": "hello\r\n\n",
+	}
+
+	assertRender(t, Strings(), vars, []renderTest{
+		{name: "upper", input: `${upper("hello")}`, expect: "HELLO"},
+		{name: "lower", input: `${lower("HELLO")}`, expect: "hello"},
+		{name: "title", input: `${title(name)}`, expect: "Hello World"},
+		{name: "trimspace", input: `${trimspace("  hello  ")}`, expect: "hello"},
+		{name: "trim", input: `${trim("?!hello?!", "!?")}`, expect: "hello"},
+		{name: "trimprefix", input: `${trimprefix("helloworld", "hello")}`, expect: "world"},
+		{name: "trimsuffix", input: `${trimsuffix("helloworld", "world")}`, expect: "hello"},
+		{name: "replace", input: `${replace("1 + 2 + 3", "+", "-")}`, expect: "1 - 2 - 3"},
+		{name: "replace regex", input: `${replace("hello world", "/w(or)ld/", "$1")}`, expect: "hello or"},
+		{name: "replace invalid regex", input: `${replace("hello", "/(/", "")}`, isError: true},
+		{name: "split and join", input: `${join("-", split(",", "a,b,c"))}`, expect: "a-b-c"},
+		{name: "join multiple lists", input: `${join(",", list, list)}`, expect: "a,b,c,a,b,c"},
+		{name: "join requires list", input: `${join(",")}`, isError: true},
+		{name: "indent", input: `${indent(2, multi)}`, expect: "line1\n  line2\n  line3"},
+		{name: "chomp", input: `${chomp(newlines)}`, expect: "hello"},
+		{name: "substr", input: `${substr("hello world", 1, 4)}`, expect: "ello"},
+		{name: "substr negative offset", input: `${substr("hello world", -5, -1)}`, expect: "world"},
+		{name: "substr over length", input: `${substr("hello", 3, 10)}`, expect: "lo"},
+		{name: "substr unicode", input: `${substr("🤔🤷", 0, 1)}`, expect: "🤔"},
+		{name: "strrev", input: `${strrev("hello")}`, expect: "olleh"},
+		{name: "regex without group", input: `${regex("[a-z]+", "53453453.345345aaabbbccc23454")}`, expect: "aaabbbccc"},
+		{name: "regex unnamed group", input: `${join(",", regex("([0-9]+)-([0-9]+)", "2019-02-01"))}`, expect: "2019,02"},
+		{name: "regex not matched", input: `${regex("[0-9]+", "abc")}`, isError: true},
+		{name: "regex mixed group", input: `${regex("(?P<a>x)(y)", "xy")}`, isError: true},
+		{name: "regexall", input: `${join(",", regexall("[a-z]+", "1234abcd5678efgh9"))}`, expect: "abcd,efgh"},
+		{name: "regexall not matched", input: `${regexall("[a-z]+", "1234")}`, expect: "[]"},
+	})
+}
+
+func TestRegexNamedGroup(t *testing.T) {
+	ret, err := regex(`^(?:(?P<scheme>[^:/?#]+):)?//(?P<host>[^/]+)`, "https://example.com/path")
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	expect := map[string]string{"scheme": "https", "host": "example.com"}
+	if diff := cmp.Diff(expect, ret); diff != "" {
+		t.Errorf("Regex result mismatch, diff=%s", diff)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	vars := tender.Variables{
+		"list":   []string{"a", "b"},
+		"num":    []int{1, 2},
+		"obj":    map[string]any{"b": 1, "a": "<tag>"},
+		"one":    []string{"a"},
+		"quoted": `a"b`,
+	}
+
+	assertRender(t, Strings(), vars, []renderTest{
+		{name: "string and number", input: `${format("Hello, %s! You are %d.", "tender", 3)}`, expect: "Hello, tender! You are 3."},
+		{name: "percent escape", input: `${format("100%%")}`, expect: "100%"},
+		{name: "float precision", input: `${format("%.2f", 3.14159)}`, expect: "3.14"},
+		{name: "width and flag", input: `${format("[%-5s][%05d]", "ab", 42)}`, expect: "[ab   ][00042]"},
+		{name: "hex", input: `${format("%x %X %o %b", 255, 255, 8, 5)}`, expect: "ff FF 10 101"},
+		{name: "bool", input: `${format("%t", true)}`, expect: "true"},
+		{name: "default value", input: `${format("%v %v %v", "s", 1.5, true)}`, expect: "s 1.5 true"},
+		{name: "list default value", input: `${format("%v", list)}`, expect: `["a","b"]`},
+		{name: "json value", input: `${format("%#v", obj)}`, expect: `{"a":"\u003ctag\u003e","b":1}`},
+		{name: "json string", input: `${format("%#v", "str")}`, expect: `"str"`},
+		{name: "quoted string", input: `${format("%q", quoted)}`, expect: `"a\"b"`},
+		{name: "explicit argument index", input: `${format("%[2]s %[1]s", "world", "hello")}`, expect: "hello world"},
+		{name: "number string to integer", input: `${format("%d", "12")}`, expect: "12"},
+		{name: "fractional number for integer", input: `${format("%d", 1.5)}`, isError: true},
+		{name: "not enough arguments", input: `${format("%s %s", "a")}`, isError: true},
+		{name: "too many arguments", input: `${format("%s", "a", "b")}`, isError: true},
+		{name: "unsupported verb", input: `${format("%z", "a")}`, isError: true},
+		{name: "formatlist", input: `${join(",", formatlist("%s=%d", list, num))}`, expect: "a=1,b=2"},
+		{name: "formatlist repeat scalar", input: `${join(",", formatlist("%s-%s", "x", list))}`, expect: "x-a,x-b"},
+		{name: "formatlist mismatch length", input: `${formatlist("%s%s", list, one)}`, isError: true},
+	})
+}
@@ -1,13 +1,12 @@
 package tender
 
 import (
+	"bytes"
 	"io"
 	"reflect"
 	"strings"
 
 	"github.com/pkg/errors"
-	"github.com/ysugimoto/tender/lexer"
-	"github.com/ysugimoto/tender/parser"
 	"github.com/ysugimoto/tender/value"
 )
 
@@ -15,15 +14,14 @@ import (
 type Variables map[string]any
 
 // Template struct represents template renderer.
-// This struct holds some state that global variables which is provided via caller,
-// and assigning local variables.
+// This struct holds global variables which is provided via caller and rendering options.
+// The template is compiled at the first rendering, and reused for the following renderings.
+// If you'd like to render the same template concurrently, use Compile() instead.
 type Template struct {
-	reader io.Reader
-	global value.Value
-	locals []value.Value
-
-	// Option value fields
-	enableEscape bool
+	reader   io.Reader
+	compiled *Compiled
+	global   value.Value
+	options  []RenderOption
 }
 
 // Shorthand render function from string
@@ -38,17 +36,11 @@ func RenderStream(r io.Reader, vars Variables, opts ...RenderOption) (string, er
 
 // Create template pointer from io.Reader stream
 func New(r io.Reader, opts ...RenderOption) *Template {
-	t := &Template{
-		reader: r,
-		global: value.Value{},
-		locals: []value.Value{},
-	}
-
-	for i := range opts {
-		opts[i](t)
+	return &Template{
+		reader:  r,
+		global:  value.Value{},
+		options: opts,
 	}
-
-	return t
 }
 
 // Create template pointer from template string
@@ -68,10 +60,26 @@ func (t *Template) With(variables Variables) *Template {
 // This method may return erorr as second return value,
 // you can handle the error if your template has syntax, typing problem
 func (t *Template) Render() (string, error) {
-	nodes, err := parser.New(lexer.New(t.reader)).Parse()
-	if err != nil {
+	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
+	defer pool.Put(buf)
+
+	buf.Reset()
+	if err := t.RenderTo(buf); err != nil {
 		return "", errors.WithStack(err)
 	}
+	return buf.String(), nil
+}
+
+// RenderTo renders the template with provided variables and writes output to the writer incrementally.
+// It is useful for rendering large output to the file or HTTP response without building whole string
+func (t *Template) RenderTo(w io.Writer) error {
+	if t.compiled == nil {
+		compiled, err := Compile(t.reader)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		t.compiled = compiled
+	}
 
-	return t.render(nodes)
+	return t.compiled.execute(w, t.global, t.options)
 }
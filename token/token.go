@@ -37,6 +37,7 @@ const (
 	FLOAT  = "FLOAT"
 	TRUE   = "TRUE"
 	FALSE  = "FALSE"
+	NULL   = "NULL"
 	LF     = "LF"
 
 	// Template spec
@@ -45,6 +46,11 @@ const (
 	CONTROL_START = "CONTROL_START" // "%{"
 	CONTROL_END   = "CONTROL_END"   // "}"
 
+	// String template spec
+	TEMPLATE_START = "TEMPLATE_START" // opening quote of string which contains template sequence
+	TEMPLATE_END   = "TEMPLATE_END"   // closing quote of string template or heredoc marker
+	HEREDOC        = "HEREDOC"        // "<<EOT" or "<<-EOT"
+
 	// Operators
 	EQUAL              = "EQUAL"              // "=="
 	NOT_EQUAL          = "NOTEQUAL"           // "!="
@@ -54,16 +60,31 @@ const (
 	LESS_THAN_EQUAL    = "LESS_THAN_EQUAL"    // <="
 	AND                = "AND"                // "&&"
 	OR                 = "OR"                 // "||"
+	PLUS               = "PLUS"               // "+"
+	ASTERISK           = "ASTERISK"           // "*"
+	SLASH              = "SLASH"              // "/"
+	PERCENT            = "PERCENT"            // "%"
+	PIPE               = "PIPE"               // "|"
+	ASSIGN             = "ASSIGN"             // "="
+	ARROW              = "ARROW"              // "=>"
 
 	// Punctuation
 	LEFT_PAREN    = "LEFT_PAREN"    // "("
 	RIGHT_PAREN   = "RIGHT_PAREN"   // ")"
 	LEFT_BRACKET  = "LEFT_BRACKET"  // "["
 	RIGHT_BRACKET = "RIGHT_BRACKET" // "]"
+	LEFT_BRACE    = "LEFT_BRACE"    // "{"
+	RIGHT_BRACE   = "RIGHT_BRACE"   // "}"
 	COMMA         = "COMMA"         // ","
 	NOT           = "NOT"           // "!"
 	TILDA         = "TILDA"         // "~"
 	MINUS         = "MINUS"         // "-"
+	QUESTION      = "QUESTION"      // "?"
+	COALESCE      = "COALESCE"      // "??"
+	OPTIONAL      = "OPTIONAL"      // "?."
+	COLON         = "COLON"         // ":"
+	ELLIPSIS      = "ELLIPSIS"      // "..."
+	DOT           = "DOT"           // "."
 
 	// Keywords
 	FOR    = "FOR"    // for
@@ -85,6 +106,7 @@ var keywords = map[string]TokenType{
 	"endif":  ENDIF,
 	"true":   TRUE,
 	"false":  FALSE,
+	"null":   NULL,
 }
 
 func LookupIdent(ident string) TokenType {
@@ -0,0 +1,204 @@
+package value
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Convert value to the provided type.
+// This function is used for passing template values to the Go function arguments,
+// so conversion is flexible like Terraform's automatic type conversion:
+//
+// numeric, bool -> string
+// numeric string -> int, uint, float
+// "true", "false" -> bool
+// integral float -> int, uint
+// slice, map -> slice, map which element is converted recursively
+//
+// Any other values which is not assignable to the type raises an error.
+func Convert(v reflect.Value, to reflect.Type) (reflect.Value, error) {
+	v = unwrap(v)
+	if v.IsValid() && v.Type().AssignableTo(to) {
+		return v, nil
+	}
+	if IsNull(v) {
+		// Null value could be converted to nilable types
+		switch to.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func:
+			return reflect.Zero(to), nil
+		}
+		return Null, CannotConvert("null", to.String())
+	}
+
+	v = deref(v)
+	if v.Type().AssignableTo(to) {
+		return v, nil
+	}
+
+	switch to.Kind() {
+	case reflect.String:
+		switch {
+		case v.Kind() == reflect.String:
+			return v.Convert(to), nil
+		case v.Kind() == reflect.Bool, IsNumeric(v):
+			return reflect.ValueOf(ToString(v)).Convert(to), nil
+		}
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		return convertInt(v, to)
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		return convertUint(v, to)
+	case reflect.Float32, reflect.Float64:
+		return convertFloat(v, to)
+	case reflect.Bool:
+		switch v.Kind() {
+		case reflect.Bool:
+			return v.Convert(to), nil
+		case reflect.String:
+			// Only "true" and "false" are allowed like Terraform, not "1" or "TRUE" which strconv.ParseBool accepts
+			switch v.String() {
+			case "true":
+				return reflect.ValueOf(true).Convert(to), nil
+			case "false":
+				return reflect.ValueOf(false).Convert(to), nil
+			}
+		}
+	case reflect.Slice:
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			return convertSlice(v, to)
+		}
+	case reflect.Map:
+		if v.Kind() == reflect.Map {
+			return convertMap(v, to)
+		}
+	}
+
+	return Null, CannotConvert(v.Type().String(), to.String())
+}
+
+// Integer conversion raises an error if the value does not fit in the type like 300 for int8
+func convertInt(v reflect.Value, to reflect.Type) (reflect.Value, error) {
+	var i int64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		i = v.Int()
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		if v.Uint() > math.MaxInt64 {
+			return Null, CannotConvert(v.Type().String(), to.String())
+		}
+		i = int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		// float64(math.MaxInt64) is rounded up to 2^63, so the upper bound must be exclusive
+		f := v.Float()
+		if !isIntegral(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return Null, CannotConvert(v.Type().String(), to.String())
+		}
+		i = int64(f)
+	case reflect.String:
+		parsed, err := strconv.ParseInt(v.String(), 10, 64)
+		if err != nil {
+			return Null, CannotConvert(v.Type().String(), to.String())
+		}
+		i = parsed
+	default:
+		return Null, CannotConvert(v.Type().String(), to.String())
+	}
+
+	if reflect.Zero(to).OverflowInt(i) {
+		return Null, CannotConvert(v.Type().String(), to.String())
+	}
+	return reflect.ValueOf(i).Convert(to), nil
+}
+
+// Unsigned integer conversion raises an error if the value is negative or does not fit in the type
+func convertUint(v reflect.Value, to reflect.Type) (reflect.Value, error) {
+	var u uint64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		if v.Int() < 0 {
+			return Null, CannotConvert(v.Type().String(), to.String())
+		}
+		u = uint64(v.Int())
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		u = v.Uint()
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if !isIntegral(f) || f < 0 || f >= math.MaxUint64 {
+			return Null, CannotConvert(v.Type().String(), to.String())
+		}
+		u = uint64(f)
+	case reflect.String:
+		parsed, err := strconv.ParseUint(v.String(), 10, 64)
+		if err != nil {
+			return Null, CannotConvert(v.Type().String(), to.String())
+		}
+		u = parsed
+	default:
+		return Null, CannotConvert(v.Type().String(), to.String())
+	}
+
+	if reflect.Zero(to).OverflowUint(u) {
+		return Null, CannotConvert(v.Type().String(), to.String())
+	}
+	return reflect.ValueOf(u).Convert(to), nil
+}
+
+// Float conversion raises an error if the value exceeds the range like 1e300 for float32
+func convertFloat(v reflect.Value, to reflect.Type) (reflect.Value, error) {
+	var f float64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		f = float64(v.Int())
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		f = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		f = v.Float()
+	case reflect.String:
+		parsed, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return Null, CannotConvert(v.Type().String(), to.String())
+		}
+		f = parsed
+	default:
+		return Null, CannotConvert(v.Type().String(), to.String())
+	}
+
+	if reflect.Zero(to).OverflowFloat(f) {
+		return Null, CannotConvert(v.Type().String(), to.String())
+	}
+	return reflect.ValueOf(f).Convert(to), nil
+}
+
+// Report the float has no fractional part, ±Inf and NaN are not integral
+func isIntegral(f float64) bool {
+	return !math.IsInf(f, 0) && !math.IsNaN(f) && f == math.Trunc(f)
+}
+
+func convertSlice(v reflect.Value, to reflect.Type) (reflect.Value, error) {
+	converted := reflect.MakeSlice(to, v.Len(), v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elem, err := Convert(v.Index(i), to.Elem())
+		if err != nil {
+			return Null, err
+		}
+		converted.Index(i).Set(elem)
+	}
+	return converted, nil
+}
+
+func convertMap(v reflect.Value, to reflect.Type) (reflect.Value, error) {
+	converted := reflect.MakeMapWithSize(to, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := Convert(iter.Key(), to.Key())
+		if err != nil {
+			return Null, err
+		}
+		elem, err := Convert(iter.Value(), to.Elem())
+		if err != nil {
+			return Null, err
+		}
+		converted.SetMapIndex(key, elem)
+	}
+	return converted, nil
+}
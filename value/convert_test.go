@@ -0,0 +1,112 @@
+package value
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConvert(t *testing.T) {
+	str := "foo"
+
+	tests := []struct {
+		name    string
+		input   any
+		to      any
+		expect  any
+		isError bool
+	}{
+		{name: "assignable", input: "foo", to: "", expect: "foo"},
+		{name: "pointer deref", input: &str, to: "", expect: "foo"},
+		{name: "int to string", input: 10, to: "", expect: "10"},
+		{name: "float to string", input: 1.5, to: "", expect: "1.5"},
+		{name: "bool to string", input: true, to: "", expect: "true"},
+		{name: "slice to string", input: []string{"a"}, to: "", isError: true},
+		{name: "int64 to int", input: int64(10), to: int(0), expect: int(10)},
+		{name: "uint to int8", input: uint(10), to: int8(0), expect: int8(10)},
+		{name: "integral float to int", input: float64(10), to: int(0), expect: int(10)},
+		{name: "fractional float to int", input: float64(1.5), to: int(0), isError: true},
+		{name: "numeric string to int", input: "10", to: int(0), expect: int(10)},
+		{name: "not numeric string to int", input: "foo", to: int(0), isError: true},
+		{name: "negative int to uint", input: -1, to: uint(0), isError: true},
+		{name: "int to uint", input: 1, to: uint(0), expect: uint(1)},
+		{name: "int to float", input: 1, to: float64(0), expect: float64(1)},
+		{name: "numeric string to float", input: "1.5", to: float64(0), expect: float64(1.5)},
+		{name: "int fits int8", input: 127, to: int8(0), expect: int8(127)},
+		{name: "int overflows int8", input: 300, to: int8(0), isError: true},
+		{name: "negative int overflows int8", input: -129, to: int8(0), isError: true},
+		{name: "uint overflows int32", input: uint64(math.MaxUint32), to: int32(0), isError: true},
+		{name: "max uint64 to int64", input: uint64(math.MaxUint64), to: int64(0), isError: true},
+		{name: "numeric string overflows int16", input: "40000", to: int16(0), isError: true},
+		{name: "float overflows int8", input: float64(200), to: int8(0), isError: true},
+		{name: "float overflows int64", input: float64(1e19), to: int64(0), isError: true},
+		{name: "infinity to int", input: math.Inf(1), to: int(0), isError: true},
+		{name: "negative infinity to int", input: math.Inf(-1), to: int64(0), isError: true},
+		{name: "NaN to int", input: math.NaN(), to: int(0), isError: true},
+		{name: "int overflows uint8", input: 256, to: uint8(0), isError: true},
+		{name: "uint fits uint8", input: uint64(255), to: uint8(0), expect: uint8(255)},
+		{name: "uint overflows uint16", input: uint64(70000), to: uint16(0), isError: true},
+		{name: "float overflows uint64", input: float64(1e20), to: uint64(0), isError: true},
+		{name: "infinity to uint", input: math.Inf(1), to: uint(0), isError: true},
+		{name: "float overflows float32", input: float64(1e300), to: float32(0), isError: true},
+		{name: "float fits float32", input: float64(1.5), to: float32(0), expect: float32(1.5)},
+		{name: "bool string to bool", input: "true", to: false, expect: true},
+		{name: "false string to bool", input: "false", to: false, expect: false},
+		{name: "numeric string to bool", input: "1", to: false, isError: true},
+		{name: "upper case string to bool", input: "TRUE", to: false, isError: true},
+		{name: "short string to bool", input: "t", to: false, isError: true},
+		{name: "int to bool", input: 1, to: false, isError: true},
+		{name: "any slice to string slice", input: []any{"a", 1}, to: []string{}, expect: []string{"a", "1"}},
+		{name: "array to slice", input: [2]int{1, 2}, to: []int64{}, expect: []int64{1, 2}},
+		{name: "invalid slice element", input: []any{"a", []int{}}, to: []string{}, isError: true},
+		{name: "map conversion", input: map[string]any{"a": 1}, to: map[string]int64{}, expect: map[string]int64{"a": 1}},
+		{name: "invalid map value", input: map[string]any{"a": "b"}, to: map[string]int{}, isError: true},
+		{name: "struct to map", input: struct{}{}, to: map[string]any{}, isError: true},
+		{name: "nil pointer to string", input: (*string)(nil), to: "", isError: true},
+		{name: "nil pointer to pointer", input: (*string)(nil), to: (*string)(nil), expect: (*string)(nil)},
+		{name: "nil map to int", input: map[string]int(nil), to: int(0), isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := Convert(reflect.ValueOf(tt.input), reflect.TypeOf(tt.to))
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, v.Interface()); diff != "" {
+				t.Errorf("Converted value mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertToInterface(t *testing.T) {
+	anyType := reflect.TypeOf((*any)(nil)).Elem()
+
+	v, err := Convert(reflect.ValueOf([]int{1}), anyType)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if diff := cmp.Diff([]int{1}, v.Interface()); diff != "" {
+		t.Errorf("Converted value mismatch, diff=%s", diff)
+	}
+
+	v, err = Convert(Null, anyType)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if !v.IsZero() {
+		t.Errorf("Expects zero value for null, got %v", v)
+	}
+}
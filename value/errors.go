@@ -1,34 +1,53 @@
 package value
 
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrMissingValue is a cause of the errors which are raised by accessing missing data like undefined key.
+// It could be detected by errors.Is to fallback to null or default value
+var ErrMissingValue = errors.New("missing value")
+
 type ValueError struct {
 	Message string
+	missing bool
 }
 
 func (e *ValueError) Error() string {
 	return e.Message
 }
 
+// Is reports the error is caused by missing data for errors.Is(err, ErrMissingValue)
+func (e *ValueError) Is(target error) bool {
+	return e.missing && target == ErrMissingValue
+}
+
 func UndefinedVariable(name string) *ValueError {
 	return &ValueError{
 		Message: `Undefined variable "` + name + `"`,
+		missing: true,
 	}
 }
 
-func UndefinedIndex(name, index string) *ValueError {
+func IndexOutOfRange(index, length int) *ValueError {
 	return &ValueError{
-		Message: `Undefined index "` + index + `" for slice value of "` + name + `"`,
+		Message: `Index ` + strconv.Itoa(index) + ` is out of range for length ` + strconv.Itoa(length),
+		missing: true,
 	}
 }
 
 func UndefinedKey(name, key string) *ValueError {
 	return &ValueError{
 		Message: `Undefined key "` + key + `" for map value of "` + name + `"`,
+		missing: true,
 	}
 }
 
 func UndefinedField(name, field string) *ValueError {
 	return &ValueError{
 		Message: `Undefined field "` + field + `" for struct value of "` + name + `"`,
+		missing: true,
 	}
 }
 
@@ -38,6 +57,13 @@ func InvalidFieldAccess(name, field string) *ValueError {
 	}
 }
 
+func NullAccess(name, field string) *ValueError {
+	return &ValueError{
+		Message: `Cannot access "` + field + `" for null value of "` + name + `"`,
+		missing: true,
+	}
+}
+
 func UnaccessibleIndex(name, index string) *ValueError {
 	return &ValueError{
 		Message: `Unaccessible index "` + index + `" for slice "` + name + `"`,
@@ -81,3 +107,27 @@ func NotTruthy(name string) *ValueError {
 		Message: name + ` is not truthy type. it must be bool or string`,
 	}
 }
+
+func DivisionByZero() *ValueError {
+	return &ValueError{
+		Message: `Division by zero`,
+	}
+}
+
+func IntegerOverflow(operator string) *ValueError {
+	return &ValueError{
+		Message: `Integer overflow in "` + operator + `" operation`,
+	}
+}
+
+func IntegerOutOfRange(value string) *ValueError {
+	return &ValueError{
+		Message: `Integer ` + value + ` is out of range of int64`,
+	}
+}
+
+func CannotConvert(from, to string) *ValueError {
+	return &ValueError{
+		Message: `Cannot convert "` + from + `" value to "` + to + `"`,
+	}
+}
@@ -2,7 +2,9 @@ package value
 
 import (
 	"bytes"
+	"math"
 	"reflect"
+	"strconv"
 	"sync"
 )
 
@@ -13,14 +15,25 @@ const (
 	dot
 	sliceBracket
 	mapBracket
+	splat       // full splat like "foo[*].bar"
+	legacySplat // attribute-only splat like "foo.*.bar"
 )
 
 type Field struct {
 	name   string
 	syntax fieldSyntax
+	// optional is true for the field which is accessed by optional chaining like "foo?.bar"
+	optional bool
 }
 
 func (f Field) String() string {
+	if f.optional {
+		if f.syntax == dot {
+			return "?." + f.name
+		}
+		return "?." + Field{name: f.name, syntax: f.syntax}.String()
+	}
+
 	switch f.syntax {
 	case sliceBracket:
 		return "[" + f.name + "]"
@@ -28,6 +41,10 @@ func (f Field) String() string {
 		return "[\"" + f.name + "\"]"
 	case dot:
 		return "." + f.name
+	case splat:
+		return "[*]"
+	case legacySplat:
+		return ".*"
 	default:
 		return f.name
 	}
@@ -40,28 +57,45 @@ var pool = sync.Pool{
 }
 
 func parseFields(ident string) (Field, []Field) {
-	parsed := make([]Field, 8)
-	index := 0
+	parsed := make([]Field, 0, 8)
 
 	buf := pool.Get().(*bytes.Buffer) // nolint:errcheck
 	defer pool.Put(buf)
 
 	buf.Reset()
 	syntax := none
+	optional := false
+
+	// Append field which is read, following field of "?." is optional
+	appendField := func(name string) {
+		parsed = append(parsed, Field{name: name, syntax: syntax, optional: optional})
+		optional = false
+	}
 
 	for i := 0; i < len(ident); i++ {
 		switch ident[i] {
+		case '?':
+			// Optional chaining like "foo?.bar" or "foo?.[0]"
+			if buf.Len() > 0 {
+				appendField(buf.String())
+				buf.Reset()
+			}
+			optional = true
 		case '.':
 			if buf.Len() > 0 {
-				parsed[index] = Field{name: buf.String(), syntax: syntax}
-				index++
-				syntax = dot
+				appendField(buf.String())
 				buf.Reset()
 			}
+			syntax = dot
+
+			// Legacy splat like "foo.*.bar"
+			if i+1 < len(ident) && ident[i+1] == '*' {
+				parsed = append(parsed, Field{name: "*", syntax: legacySplat})
+				i++
+			}
 		case '[':
 			if buf.Len() > 0 {
-				parsed[index] = Field{name: buf.String(), syntax: syntax}
-				index++
+				appendField(buf.String())
 				buf.Reset()
 			}
 
@@ -78,8 +112,11 @@ func parseFields(ident string) (Field, []Field) {
 				buf.WriteByte(ident[j])
 			}
 			i = j
-			parsed[index] = Field{name: buf.String(), syntax: syntax}
-			index++
+			// Full splat like "foo[*].bar"
+			if syntax == sliceBracket && buf.String() == "*" {
+				syntax = splat
+			}
+			appendField(buf.String())
 			syntax = none
 			buf.Reset()
 		default:
@@ -88,51 +125,110 @@ func parseFields(ident string) (Field, []Field) {
 	}
 
 	if buf.Len() > 0 {
-		parsed[index] = Field{name: buf.String(), syntax: syntax}
-		index++
+		appendField(buf.String())
 	}
-	return parsed[0], parsed[1:index]
+	if len(parsed) == 0 {
+		return Field{syntax: none}, nil
+	}
+	return parsed[0], parsed[1:]
 }
 
+// Dereference pointer and interface value to the underlying concrete value.
+// Returns invalid value if nil pointer or nil interface is found
 func deref(v reflect.Value) reflect.Value {
-	if v.Type().Kind() == reflect.Ptr {
-		return v.Elem()
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		v = v.Elem()
+	}
+	return v
+}
+
+// Unwrap interface value to the underlying concrete value
+func unwrap(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Interface {
+		v = v.Elem()
 	}
 	return v
 }
 
+// LookupField finds the exported field of the struct value by name, including promoted fields of embedded structs.
+// found is false if the field does not exist, and returned value is invalid if the field is promoted
+// through nil embedded pointer, reflect.Value.FieldByName causes panic in this case
+func LookupField(v reflect.Value, name string) (reflect.Value, bool) {
+	field, ok := v.Type().FieldByName(name)
+	if !ok || !field.IsExported() {
+		return Null, false
+	}
+	fv, err := v.FieldByIndexErr(field.Index)
+	if err != nil {
+		return Null, true
+	}
+	return fv, true
+}
+
+// IsNull reports the value is null like Terraform.
+// Invalid value, nil pointer, nil interface and nil map are treated as null
+func IsNull(v reflect.Value) bool {
+	v = deref(v)
+	if !v.IsValid() {
+		return true
+	}
+	return v.Kind() == reflect.Map && v.IsNil()
+}
+
+// TypeName returns kind name of the value for error message, "null" for null value
+func TypeName(v reflect.Value) string {
+	if IsNull(v) {
+		return "null"
+	}
+	return deref(v).Kind().String()
+}
+
 func IsSlice(v reflect.Value) bool {
-	return deref(v).Type().Kind() == reflect.Slice
+	v = deref(v)
+	return v.IsValid() && v.Kind() == reflect.Slice
 }
 
 func IsMap(v reflect.Value) bool {
-	return deref(v).Type().Kind() == reflect.Map
+	v = deref(v)
+	return v.IsValid() && v.Kind() == reflect.Map
 }
 
 func IsStruct(v reflect.Value) bool {
-	return deref(v).Type().Kind() == reflect.Struct
+	v = deref(v)
+	return v.IsValid() && v.Kind() == reflect.Struct
 }
 
 func IsBool(v reflect.Value) bool {
-	return deref(v).Type().Kind() == reflect.Bool
+	v = deref(v)
+	return v.IsValid() && v.Kind() == reflect.Bool
 }
 
 func IsNumeric(v reflect.Value) bool {
-	switch v.Type().Kind() {
-	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Float32, reflect.Float64:
+	v = deref(v)
+	if !v.IsValid() {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Float32, reflect.Float64,
+		reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
 		return true
 	}
 	return false
 }
 
+// Null value is not truthy like Terraform, it raises an error
 func IsThuthy(v reflect.Value) (bool, error) {
-	switch v.Type().Kind() {
+	if IsNull(v) {
+		return false, NotTruthy("null")
+	}
+	v = deref(v)
+	switch v.Kind() {
 	case reflect.Bool:
 		return v.Bool(), nil
 	case reflect.String:
 		return v.String() != "", nil
 	default:
-		return false, NotTruthy(v.Type().Kind().String())
+		return false, NotTruthy(v.Kind().String())
 	}
 }
 
@@ -148,31 +244,63 @@ func IsThuthy(v reflect.Value) (bool, error) {
 // The reason why is the main logics must have acculate type conversions,
 // but a template is just "view", so types should be flexible to avoid annying type conversions.
 func toComparableTypes(left, right reflect.Value) (reflect.Value, reflect.Value, error) {
-	if !left.Comparable() {
+	if IsNull(left) || !left.Comparable() {
 		return left, right, NotComparable("left expression")
 	}
-	if !right.Comparable() {
+	if IsNull(right) || !right.Comparable() {
 		return left, right, NotComparable("right expression")
 	}
 
-	left = deref(left)
-	switch left.Kind() {
-	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
-		left = reflect.ValueOf(left.Int())
-	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
-		left = reflect.ValueOf(int64(left.Uint()))
-	case reflect.Float32, reflect.Float64:
-		left = reflect.ValueOf(left.Float())
+	left, err := toComparableType(left)
+	if err != nil {
+		return left, right, err
+	}
+	right, err = toComparableType(right)
+	if err != nil {
+		return left, right, err
 	}
 
-	right = deref(right)
-	switch right.Kind() {
+	return left, right, nil
+}
+
+// Unsigned integer which exceeds int64 could not be compared or calculated with other integers,
+// raise an error instead of wrapping around to negative value
+func toComparableType(v reflect.Value) (reflect.Value, error) {
+	v = deref(v)
+	switch v.Kind() {
 	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
-		right = reflect.ValueOf(right.Int())
+		return reflect.ValueOf(v.Int()), nil
 	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
-		right = reflect.ValueOf(int64(right.Uint()))
+		if v.Uint() > math.MaxInt64 {
+			return v, IntegerOutOfRange(strconv.FormatUint(v.Uint(), 10))
+		}
+		return reflect.ValueOf(int64(v.Uint())), nil
 	case reflect.Float32, reflect.Float64:
-		right = reflect.ValueOf(right.Float())
+		return reflect.ValueOf(v.Float()), nil
+	}
+	return v, nil
+}
+
+// Arithmetic operation types are the same as comparison types, but only accepts numeric values.
+// Additionally, if either of value is float64, another one is also promoted to float64
+// because arithmetic operation between int and float is natural in templating.
+func toArithmeticTypes(left, right reflect.Value) (reflect.Value, reflect.Value, error) {
+	if !IsNumeric(left) {
+		return left, right, NotNumeric(TypeName(left))
+	} else if !IsNumeric(right) {
+		return left, right, NotNumeric(TypeName(right))
+	}
+
+	left, right, err := toComparableTypes(left, right)
+	if err != nil {
+		return left, right, err
+	}
+
+	switch {
+	case left.Kind() == reflect.Float64 && right.Kind() == reflect.Int64:
+		right = reflect.ValueOf(float64(right.Int()))
+	case left.Kind() == reflect.Int64 && right.Kind() == reflect.Float64:
+		left = reflect.ValueOf(float64(left.Int()))
 	}
 
 	return left, right, nil
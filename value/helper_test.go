@@ -48,6 +48,36 @@ func TestParseFields(t *testing.T) {
 				{name: "0", syntax: sliceBracket},
 			},
 		},
+		{
+			name:  "optional chaining",
+			input: `foo?.bar.baz?.[0]`,
+			expect: []Field{
+				{name: "foo", syntax: none},
+				{name: "bar", syntax: dot, optional: true},
+				{name: "baz", syntax: dot},
+				{name: "0", syntax: sliceBracket, optional: true},
+			},
+		},
+		{
+			name:  "full splat",
+			input: `foo[*].bar[0]`,
+			expect: []Field{
+				{name: "foo", syntax: none},
+				{name: "*", syntax: splat},
+				{name: "bar", syntax: dot},
+				{name: "0", syntax: sliceBracket},
+			},
+		},
+		{
+			name:  "legacy splat",
+			input: `foo.*.bar.baz`,
+			expect: []Field{
+				{name: "foo", syntax: none},
+				{name: "*", syntax: legacySplat},
+				{name: "bar", syntax: dot},
+				{name: "baz", syntax: dot},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -97,3 +127,56 @@ func TestIsStruct(t *testing.T) {
 	}
 
 }
+
+func TestIsNull(t *testing.T) {
+	var nilAny any
+	tests := []struct {
+		input  reflect.Value
+		expect bool
+	}{
+		{input: Null, expect: true},
+		{input: reflect.ValueOf((*int)(nil)), expect: true},
+		{input: reflect.ValueOf(map[string]int(nil)), expect: true},
+		{input: reflect.ValueOf(&nilAny).Elem(), expect: true},
+		{input: reflect.ValueOf([]int(nil)), expect: false},
+		{input: reflect.ValueOf(0), expect: false},
+		{input: reflect.ValueOf(""), expect: false},
+		{input: reflect.ValueOf(map[string]int{}), expect: false},
+	}
+
+	for i, tt := range tests {
+		if diff := cmp.Diff(tt.expect, IsNull(tt.input)); diff != "" {
+			t.Errorf("[%d] IsNull unmatch, diff=%s", i, diff)
+		}
+	}
+}
+
+func TestPredicatesForNull(t *testing.T) {
+	var nilAny any
+	inputs := []reflect.Value{
+		Null,
+		reflect.ValueOf((*int)(nil)),
+		reflect.ValueOf((*[]int)(nil)),
+		reflect.ValueOf(&nilAny).Elem(),
+	}
+
+	for i, v := range inputs {
+		for name, fn := range map[string]func(reflect.Value) bool{
+			"IsSlice":   IsSlice,
+			"IsMap":     IsMap,
+			"IsStruct":  IsStruct,
+			"IsBool":    IsBool,
+			"IsNumeric": IsNumeric,
+		} {
+			if fn(v) {
+				t.Errorf("[%d] %s expects false for null", i, name)
+			}
+		}
+		if _, err := IsThuthy(v); err == nil {
+			t.Errorf("[%d] IsThuthy expects error for null", i)
+		}
+		if diff := cmp.Diff("null", TypeName(v)); diff != "" {
+			t.Errorf("[%d] TypeName unmatch, diff=%s", i, diff)
+		}
+	}
+}
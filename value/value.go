@@ -3,6 +3,7 @@ package value
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"reflect"
 	"sort"
 	"strconv"
@@ -28,48 +29,144 @@ func (v Value) Resolve(ident string) (reflect.Value, error) {
 	defer pool.Put(names)
 
 	names.Reset()
+	names.WriteString(first.name)
 
-	child := deref(variable)
-	for _, field := range subFields {
-		switch {
-		case IsMap(child):
-			child = child.MapIndex(reflect.ValueOf(field.name))
-			if child == zero {
-				return Null, UndefinedKey(names.String(), field.name)
-			}
-			child = reflect.ValueOf(child.Interface())
-		case IsSlice(child):
-			idx, err := strconv.Atoi(field.name)
-			if err != nil {
-				return Null, UnaccessibleIndex(names.String(), field.name)
-			}
-			if idx > child.Len()-1 {
-				return Null, UndefinedIndex(names.String(), field.name)
-			}
-			child = reflect.ValueOf(child.Index(idx).Interface())
-		case IsStruct(child):
-			// Struct field must start with Upper-case alphabet, valid field name
-			// otherwise reflect.Value.FieldByName will cause panic
-			if field.name[0] < 0x41 || field.name[0] > 0x5A {
-				return Null, InvalidFieldAccess(names.String(), field.name)
-			}
-			child = child.FieldByName(field.name)
-			if child == zero {
-				return Null, UndefinedField(names.String(), field.name)
+	return resolveFields(deref(variable), subFields, names)
+}
+
+// Resolve fields from the value, names holds accessed field names for error message.
+// Optional field like "foo?.bar" yields null instead of an error when the data is missing
+func resolveFields(child reflect.Value, fields []Field, names *bytes.Buffer) (reflect.Value, error) {
+	for i, field := range fields {
+		if field.syntax == splat || field.syntax == legacySplat {
+			return resolveSplat(child, field, fields[i+1:], names)
+		}
+
+		v, err := resolveField(child, field, names)
+		if err != nil {
+			if !field.optional || !errors.Is(err, ErrMissingValue) {
+				return Null, err
 			}
-			child = reflect.ValueOf(child.Interface())
-		default:
-			return Null, UndefinedVariable(field.name)
+			v = Null
 		}
-		child = deref(child)
+		child = deref(v)
 		names.WriteString(field.String())
 	}
 
 	return child, nil
 }
 
-// Compare values with "==" operator
+// Resolve single field from the value
+func resolveField(child reflect.Value, field Field, names *bytes.Buffer) (reflect.Value, error) {
+	switch {
+	case IsNull(child):
+		return Null, NullAccess(names.String(), field.String())
+	case IsMap(child):
+		// Convert key to the map key type like map[int]string
+		key, err := Convert(reflect.ValueOf(field.name), child.Type().Key())
+		if err != nil {
+			return Null, UndefinedKey(names.String(), field.name)
+		}
+		v := child.MapIndex(key)
+		if v == zero {
+			return Null, UndefinedKey(names.String(), field.name)
+		}
+		return reflect.ValueOf(v.Interface()), nil
+	case IsSlice(child):
+		idx, err := strconv.Atoi(field.name)
+		if err != nil {
+			return Null, UnaccessibleIndex(names.String(), field.name)
+		}
+		if idx < 0 || idx > child.Len()-1 {
+			return Null, IndexOutOfRange(idx, child.Len())
+		}
+		return reflect.ValueOf(child.Index(idx).Interface()), nil
+	case IsStruct(child):
+		if field.name == "" {
+			return Null, UndefinedField(names.String(), field.name)
+		}
+		// Struct field must start with Upper-case alphabet, valid field name
+		if field.name[0] < 0x41 || field.name[0] > 0x5A {
+			return Null, InvalidFieldAccess(names.String(), field.name)
+		}
+		v, found := LookupField(child, field.name)
+		if !found {
+			return Null, UndefinedField(names.String(), field.name)
+		}
+		if !v.IsValid() {
+			return Null, NullAccess(names.String(), field.name)
+		}
+		return reflect.ValueOf(v.Interface()), nil
+	default:
+		return Null, UndefinedVariable(field.name)
+	}
+}
+
+// Resolve splat expression like Terraform.
+// Full splat "[*]" applies all following fields to each element,
+// and legacy splat ".*" applies only following attribute access to each element,
+// and then remaining fields are applied to the result list.
+// If splat is applied to non-list value, the value is treated as single element list,
+// and null value is treated as empty list.
+func resolveSplat(child reflect.Value, field Field, rest []Field, names *bytes.Buffer) (reflect.Value, error) {
+	each, after := rest, []Field{}
+	if field.syntax == legacySplat {
+		for i := range rest {
+			if rest[i].syntax != dot {
+				each, after = rest[:i], rest[i:]
+				break
+			}
+		}
+	}
+
+	var elements []reflect.Value
+	switch {
+	case IsNull(child):
+		elements = []reflect.Value{}
+	case child.Kind() == reflect.Slice, child.Kind() == reflect.Array:
+		elements = make([]reflect.Value, child.Len())
+		for i := range elements {
+			elements[i] = reflect.ValueOf(child.Index(i).Interface())
+		}
+	default:
+		elements = []reflect.Value{child}
+	}
+
+	names.WriteString(field.String())
+	prefix := names.String()
+
+	result := make([]any, len(elements))
+	for i := range elements {
+		// Null element is kept as null
+		if IsNull(elements[i]) {
+			continue
+		}
+		names.Reset()
+		names.WriteString(prefix)
+		v, err := resolveFields(deref(elements[i]), each, names)
+		if err != nil {
+			return Null, err
+		}
+		if v.IsValid() {
+			result[i] = v.Interface()
+		}
+	}
+
+	names.Reset()
+	names.WriteString(prefix)
+	for i := range each {
+		names.WriteString(each[i].String())
+	}
+	return resolveFields(reflect.ValueOf(result), after, names)
+}
+
+// Compare values with "==" operator.
+// Null is equal only to null, and comparison with null never raises type mismatch error
 func Equal(left, right reflect.Value) (bool, error) {
+	if IsNull(left) || IsNull(right) {
+		return IsNull(left) && IsNull(right), nil
+	}
+
 	left, right, err := toComparableTypes(left, right)
 	if err != nil {
 		return false, errors.WithStack(err)
@@ -202,8 +299,116 @@ func LessThanEqual(left, right reflect.Value) (bool, error) {
 	}
 }
 
+// Calculate values with "+" operator.
+// Integer arithmetic which overflows int64 raises an error instead of wrapping around
+func Add(left, right reflect.Value) (reflect.Value, error) {
+	left, right, err := toArithmeticTypes(left, right)
+	if err != nil {
+		return Null, errors.WithStack(err)
+	}
+
+	if left.Kind() == reflect.Float64 {
+		return reflect.ValueOf(left.Float() + right.Float()), nil
+	}
+	l, r := left.Int(), right.Int()
+	ret := l + r
+	if (r > 0 && ret < l) || (r < 0 && ret > l) {
+		return Null, IntegerOverflow("+")
+	}
+	return reflect.ValueOf(ret), nil
+}
+
+// Calculate values with "-" operator
+func Subtract(left, right reflect.Value) (reflect.Value, error) {
+	left, right, err := toArithmeticTypes(left, right)
+	if err != nil {
+		return Null, errors.WithStack(err)
+	}
+
+	if left.Kind() == reflect.Float64 {
+		return reflect.ValueOf(left.Float() - right.Float()), nil
+	}
+	l, r := left.Int(), right.Int()
+	ret := l - r
+	if (r > 0 && ret > l) || (r < 0 && ret < l) {
+		return Null, IntegerOverflow("-")
+	}
+	return reflect.ValueOf(ret), nil
+}
+
+// Calculate values with "*" operator
+func Multiply(left, right reflect.Value) (reflect.Value, error) {
+	left, right, err := toArithmeticTypes(left, right)
+	if err != nil {
+		return Null, errors.WithStack(err)
+	}
+
+	if left.Kind() == reflect.Float64 {
+		return reflect.ValueOf(left.Float() * right.Float()), nil
+	}
+	l, r := left.Int(), right.Int()
+	ret := l * r
+	if l != 0 && (ret/l != r || (l == -1 && r == math.MinInt64)) {
+		return Null, IntegerOverflow("*")
+	}
+	return reflect.ValueOf(ret), nil
+}
+
+// Calculate values with "/" operator.
+// Like Terraform, integer division could produce fractional number, e.g 5 / 2 = 2.5.
+// So the result is int64 only when integer is divisible, otherwise float64
+func Divide(left, right reflect.Value) (reflect.Value, error) {
+	left, right, err := toArithmeticTypes(left, right)
+	if err != nil {
+		return Null, errors.WithStack(err)
+	}
+
+	if left.Kind() == reflect.Float64 {
+		if right.Float() == 0 {
+			return Null, DivisionByZero()
+		}
+		return reflect.ValueOf(left.Float() / right.Float()), nil
+	}
+
+	if right.Int() == 0 {
+		return Null, DivisionByZero()
+	}
+	if left.Int() == math.MinInt64 && right.Int() == -1 {
+		return Null, IntegerOverflow("/")
+	}
+	if left.Int()%right.Int() != 0 {
+		return reflect.ValueOf(float64(left.Int()) / float64(right.Int())), nil
+	}
+	return reflect.ValueOf(left.Int() / right.Int()), nil
+}
+
+// Calculate values with "%" operator
+func Modulo(left, right reflect.Value) (reflect.Value, error) {
+	left, right, err := toArithmeticTypes(left, right)
+	if err != nil {
+		return Null, errors.WithStack(err)
+	}
+
+	if left.Kind() == reflect.Float64 {
+		if right.Float() == 0 {
+			return Null, DivisionByZero()
+		}
+		return reflect.ValueOf(math.Mod(left.Float(), right.Float())), nil
+	}
+
+	if right.Int() == 0 {
+		return Null, DivisionByZero()
+	}
+	return reflect.ValueOf(left.Int() % right.Int()), nil
+}
+
 // Stringify reflect.Value
 func ToString(v reflect.Value) string {
+	// Null is stringified as empty string like Terraform
+	if IsNull(v) {
+		return ""
+	}
+	// Element of []any or map[string]any is wrapped by interface
 	v = deref(v)
 
 	switch v.Kind() {
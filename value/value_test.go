@@ -1,6 +1,8 @@
 package value
 
 import (
+	"errors"
+	"math"
 	"reflect"
 	"testing"
 
@@ -74,6 +76,61 @@ func TestResolveValue(t *testing.T) {
 	}
 }
 
+func TestResolveSplat(t *testing.T) {
+	type server struct {
+		Name string
+		Tags []string
+	}
+	global := Value{
+		"servers": reflect.ValueOf([]server{
+			{Name: "a", Tags: []string{"x", "y"}},
+			{Name: "b", Tags: []string{"z"}},
+		}),
+		"pointers": reflect.ValueOf([]*server{{Name: "a"}, nil}),
+		"maps": reflect.ValueOf([]any{
+			map[string]any{"id": 1},
+			map[string]any{"id": 2},
+		}),
+		"single": reflect.ValueOf(server{Name: "only"}),
+		"null":   reflect.ValueOf(nil),
+	}
+
+	tests := []struct {
+		index   string
+		expect  any
+		isError bool
+	}{
+		{index: "servers[*].Name", expect: []any{"a", "b"}},
+		{index: "servers.*.Name", expect: []any{"a", "b"}},
+		{index: "servers[*].Tags[0]", expect: []any{"x", "z"}},
+		{index: "servers.*.Tags[0]", expect: []string{"x", "y"}},
+		{index: "pointers[*].Name", expect: []any{"a", nil}},
+		{index: "maps[*].id", expect: []any{1, 2}},
+		{index: "single[*].Name", expect: []any{"only"}},
+		{index: "null[*]", expect: []any{}},
+		{index: "servers[*].Undefined", isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.index, func(t *testing.T) {
+			v, err := global.Resolve(tt.index)
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Undexpected error: %s", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, v.Interface()); diff != "" {
+				t.Errorf("Resolved Value unmatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
 func BenchmarkResolveValue(b *testing.B) {
 	global := Value{
 		"map": reflect.ValueOf(map[string]any{
@@ -94,6 +151,288 @@ type testStruct struct {
 	name string
 }
 
+func TestResolveNull(t *testing.T) {
+	type user struct {
+		Name string
+	}
+	var nilUser *user
+	var nilAny any
+
+	global := Value{
+		"user":    reflect.ValueOf(nilUser),
+		"any":     reflect.ValueOf(nilAny),
+		"map":     reflect.ValueOf(map[string]any(nil)),
+		"users":   reflect.ValueOf([]*user{nil, {Name: "foo"}}),
+		"wrapped": reflect.ValueOf(map[string]any{"user": nilUser}),
+	}
+
+	tests := []struct {
+		index   string
+		isNull  bool
+		isError bool
+	}{
+		{index: "user", isNull: true},
+		{index: "any", isNull: true},
+		{index: "map", isNull: true},
+		{index: "users[0]", isNull: true},
+		{index: "wrapped.user", isNull: true},
+		{index: "users[*].Name"},
+		{index: "user.Name", isError: true},
+		{index: `map["key"]`, isError: true},
+		{index: "wrapped.user.Name", isError: true},
+		{index: "users[0].Name", isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.index, func(t *testing.T) {
+			v, err := global.Resolve(tt.index)
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Undexpected error: %s", err)
+				return
+			}
+			if diff := cmp.Diff(tt.isNull, IsNull(v)); diff != "" {
+				t.Errorf("IsNull unmatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestResolveOptional(t *testing.T) {
+	type profile struct {
+		Nickname string
+	}
+	type user struct {
+		Profile *profile
+	}
+
+	global := Value{
+		"config": reflect.ValueOf(map[string]any{
+			"name": "foo",
+			"list": []int{1},
+		}),
+		"user":  reflect.ValueOf(&user{}),
+		"empty": reflect.ValueOf(map[string]any(nil)),
+	}
+
+	tests := []struct {
+		index   string
+		expect  any
+		isError bool
+	}{
+		{index: "config?.name", expect: "foo"},
+		{index: "config?.missing", expect: nil},
+		{index: "config?.missing?.nested", expect: nil},
+		{index: "config.list?.[5]", expect: nil},
+		{index: "config.list?.[0]", expect: 1},
+		{index: "user.Profile?.Nickname", expect: nil},
+		{index: "empty?.key", expect: nil},
+		{index: `user?.[""]`, expect: nil},
+		{index: `user[""]`, isError: true},
+		{index: "config.list[-1]", isError: true},
+		{index: "config?.missing.nested", isError: true},
+		{index: "config.missing", isError: true},
+		{index: "config?.list?.[foo]", isError: true},
+		{index: "user?.profile", isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.index, func(t *testing.T) {
+			v, err := global.Resolve(tt.index)
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Undexpected error: %s", err)
+				return
+			}
+			var actual any
+			if v.IsValid() {
+				actual = v.Interface()
+			}
+			if diff := cmp.Diff(tt.expect, actual); diff != "" {
+				t.Errorf("Resolved Value unmatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestResolveEmbeddedNilPointer(t *testing.T) {
+	type Embedded struct {
+		Name string
+	}
+	type wrapper struct {
+		*Embedded
+		X int
+	}
+
+	global := Value{
+		"nil":  reflect.ValueOf(wrapper{X: 1}),
+		"ptr":  reflect.ValueOf(&wrapper{X: 1}),
+		"full": reflect.ValueOf(wrapper{Embedded: &Embedded{Name: "foo"}}),
+	}
+
+	tests := []struct {
+		index   string
+		expect  any
+		isError bool
+	}{
+		{index: "nil.X", expect: 1},
+		{index: "ptr.X", expect: 1},
+		{index: "full.Name", expect: "foo"},
+		{index: "nil?.Name", expect: nil},
+		{index: "ptr?.Name", expect: nil},
+		{index: "nil.Name", isError: true},
+		{index: "ptr.Name", isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.index, func(t *testing.T) {
+			v, err := global.Resolve(tt.index)
+			if tt.isError {
+				if !errors.Is(err, ErrMissingValue) {
+					t.Errorf("Expects missing value error, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Undexpected error: %s", err)
+				return
+			}
+			var actual any
+			if v.IsValid() {
+				actual = v.Interface()
+			}
+			if diff := cmp.Diff(tt.expect, actual); diff != "" {
+				t.Errorf("Resolved Value unmatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestMissingValueError(t *testing.T) {
+	global := Value{
+		"config": reflect.ValueOf(map[string]any{"name": "foo"}),
+	}
+
+	tests := []struct {
+		index   string
+		missing bool
+	}{
+		{index: "undefined", missing: true},
+		{index: "config.missing", missing: true},
+		{index: "config.name.nested", missing: true},
+		{index: "config.name[0]", missing: true},
+	}
+
+	for _, tt := range tests {
+		_, err := global.Resolve(tt.index)
+		if err == nil {
+			t.Errorf("%s: Expects error, but got nil", tt.index)
+			continue
+		}
+		if diff := cmp.Diff(tt.missing, errors.Is(err, ErrMissingValue)); diff != "" {
+			t.Errorf("%s: missing value error unmatch, diff=%s", tt.index, diff)
+		}
+	}
+}
+
+func TestCompareNull(t *testing.T) {
+	tests := []struct {
+		left    any
+		right   any
+		expect  bool
+		isError bool
+	}{
+		{left: nil, right: nil, expect: true},
+		{left: (*int)(nil), right: nil, expect: true},
+		{left: map[string]int(nil), right: nil, expect: true},
+		{left: nil, right: int(0), expect: false},
+		{left: "", right: nil, expect: false},
+		{left: []int{1}, right: nil, expect: false},
+	}
+
+	for i, tt := range tests {
+		eq, err := Equal(reflect.ValueOf(tt.left), reflect.ValueOf(tt.right))
+		if err != nil {
+			t.Errorf("[%d] Expects no error, got error %s", i, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.expect, eq); diff != "" {
+			t.Errorf("[%d] Equal result mismatch, diff=%s", i, diff)
+		}
+		neq, err := NotEqual(reflect.ValueOf(tt.left), reflect.ValueOf(tt.right))
+		if err != nil {
+			t.Errorf("[%d] Expects no error, got error %s", i, err)
+			continue
+		}
+		if diff := cmp.Diff(!tt.expect, neq); diff != "" {
+			t.Errorf("[%d] NotEqual result mismatch, diff=%s", i, diff)
+		}
+	}
+
+	// Ordering comparison with null is an error
+	for _, compare := range []func(left, right reflect.Value) (bool, error){GreaterThan, GreaterThanEqual, LessThan, LessThanEqual} {
+		if _, err := compare(Null, reflect.ValueOf(1)); err == nil {
+			t.Errorf("Expects error, but got nil")
+		}
+		if _, err := compare(reflect.ValueOf(1), reflect.ValueOf((*int)(nil))); err == nil {
+			t.Errorf("Expects error, but got nil")
+		}
+	}
+}
+
+func TestToStringNull(t *testing.T) {
+	var nilAny any
+	tests := []struct {
+		input  reflect.Value
+		expect string
+	}{
+		{input: Null, expect: ""},
+		{input: reflect.ValueOf((*int)(nil)), expect: ""},
+		{input: reflect.ValueOf(map[string]int(nil)), expect: ""},
+		{input: reflect.ValueOf(&nilAny).Elem(), expect: ""},
+		{input: reflect.ValueOf([]any{1, nil, (*int)(nil)}), expect: "[1, , ]"},
+	}
+
+	for i, tt := range tests {
+		if diff := cmp.Diff(tt.expect, ToString(tt.input)); diff != "" {
+			t.Errorf("[%d] ToString result mismatch, diff=%s", i, diff)
+		}
+	}
+}
+
+func TestCompareLargeUnsignedInteger(t *testing.T) {
+	maxUint := reflect.ValueOf(uint64(math.MaxUint64))
+	maxInt := reflect.ValueOf(uint64(math.MaxInt64))
+	zero := reflect.ValueOf(0)
+
+	if _, err := Equal(maxUint, zero); err == nil {
+		t.Errorf("Equal: Expects error but got nil")
+	}
+	if _, err := GreaterThan(maxUint, zero); err == nil {
+		t.Errorf("GreaterThan: Expects error but got nil")
+	}
+	if _, err := LessThan(zero, maxUint); err == nil {
+		t.Errorf("LessThan: Expects error but got nil")
+	}
+	gt, err := GreaterThan(maxInt, zero)
+	if err != nil {
+		t.Errorf("GreaterThan: Expects no error, got error %s", err)
+	}
+	if diff := cmp.Diff(true, gt); diff != "" {
+		t.Errorf("GreaterThan result mismatch, diff=%s", diff)
+	}
+}
+
 func TestCompareValuesEqual(t *testing.T) {
 	tests := []struct {
 		left    any
@@ -1967,3 +2306,200 @@ func TestCompareValuesLessThanEqual(t *testing.T) {
 		}
 	}
 }
+
+func TestArithmeticAdd(t *testing.T) {
+	tests := []struct {
+		left    any
+		right   any
+		expect  any
+		isError bool
+	}{
+		{left: int(1), right: int(2), expect: int64(3)},
+		{left: int8(1), right: uint16(2), expect: int64(3)},
+		{left: uint64(1), right: int32(-2), expect: int64(-1)},
+		{left: int(1), right: float64(0.5), expect: float64(1.5)},
+		{left: float32(0.5), right: int64(1), expect: float64(1.5)},
+		{left: float64(0.5), right: float64(0.25), expect: float64(0.75)},
+		{left: int(1), right: "foo", isError: true},
+		{left: "foo", right: "bar", isError: true},
+		{left: true, right: int(1), isError: true},
+		{left: []int{1}, right: int(1), isError: true},
+		{left: int(1), right: testStruct{name: "foo"}, isError: true},
+		{left: nil, right: int(1), isError: true},
+		{left: int(1), right: (*int)(nil), isError: true},
+		{left: int64(math.MaxInt64 - 1), right: int(1), expect: int64(math.MaxInt64)},
+		{left: int64(math.MinInt64 + 1), right: int(-1), expect: int64(math.MinInt64)},
+		{left: int64(math.MaxInt64), right: int(1), isError: true},
+		{left: int64(math.MinInt64), right: int(-1), isError: true},
+		{left: uint64(math.MaxInt64), right: int(0), expect: int64(math.MaxInt64)},
+		{left: uint64(math.MaxUint64), right: int(1), isError: true},
+		{left: int(1), right: uint64(math.MaxInt64 + 1), isError: true},
+	}
+
+	for i, tt := range tests {
+		ret, err := Add(reflect.ValueOf(tt.left), reflect.ValueOf(tt.right))
+		if tt.isError {
+			if err == nil {
+				t.Errorf("[%d] Expects error but got nil", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Expects no error, got error %s", i, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.expect, ret.Interface()); diff != "" {
+			t.Errorf("[%d] Add result mismatch, diff=%s", i, diff)
+		}
+	}
+}
+
+func TestArithmeticSubtract(t *testing.T) {
+	tests := []struct {
+		left    any
+		right   any
+		expect  any
+		isError bool
+	}{
+		{left: int(1), right: int(2), expect: int64(-1)},
+		{left: uint8(10), right: int16(2), expect: int64(8)},
+		{left: int(1), right: float64(0.5), expect: float64(0.5)},
+		{left: float64(2.5), right: int(1), expect: float64(1.5)},
+		{left: int(1), right: "foo", isError: true},
+		{left: false, right: int(1), isError: true},
+		{left: int64(math.MinInt64 + 1), right: int(1), expect: int64(math.MinInt64)},
+		{left: int64(-1), right: int64(math.MaxInt64), expect: int64(math.MinInt64)},
+		{left: int64(math.MinInt64), right: int(1), isError: true},
+		{left: int64(math.MaxInt64), right: int(-1), isError: true},
+		{left: int(0), right: int64(math.MinInt64), isError: true},
+		{left: uint64(math.MaxUint64), right: int(1), isError: true},
+	}
+
+	for i, tt := range tests {
+		ret, err := Subtract(reflect.ValueOf(tt.left), reflect.ValueOf(tt.right))
+		if tt.isError {
+			if err == nil {
+				t.Errorf("[%d] Expects error but got nil", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Expects no error, got error %s", i, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.expect, ret.Interface()); diff != "" {
+			t.Errorf("[%d] Subtract result mismatch, diff=%s", i, diff)
+		}
+	}
+}
+
+func TestArithmeticMultiply(t *testing.T) {
+	tests := []struct {
+		left    any
+		right   any
+		expect  any
+		isError bool
+	}{
+		{left: int(3), right: int(2), expect: int64(6)},
+		{left: uint32(3), right: int8(-2), expect: int64(-6)},
+		{left: int(3), right: float64(0.5), expect: float64(1.5)},
+		{left: float32(0.5), right: float32(0.5), expect: float64(0.25)},
+		{left: int(1), right: "foo", isError: true},
+		{left: []string{"foo"}, right: int(2), isError: true},
+		{left: int64(math.MaxInt64), right: int(1), expect: int64(math.MaxInt64)},
+		{left: int64(math.MinInt64), right: int(1), expect: int64(math.MinInt64)},
+		{left: int64(math.MaxInt64/2 + 1), right: int(2), isError: true},
+		{left: int64(math.MinInt64), right: int(-1), isError: true},
+		{left: int(-1), right: int64(math.MinInt64), isError: true},
+		{left: uint(math.MaxUint64), right: int(1), isError: true},
+	}
+
+	for i, tt := range tests {
+		ret, err := Multiply(reflect.ValueOf(tt.left), reflect.ValueOf(tt.right))
+		if tt.isError {
+			if err == nil {
+				t.Errorf("[%d] Expects error but got nil", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Expects no error, got error %s", i, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.expect, ret.Interface()); diff != "" {
+			t.Errorf("[%d] Multiply result mismatch, diff=%s", i, diff)
+		}
+	}
+}
+
+func TestArithmeticDivide(t *testing.T) {
+	tests := []struct {
+		left    any
+		right   any
+		expect  any
+		isError bool
+	}{
+		{left: int(6), right: int(2), expect: int64(3)},
+		{left: int(5), right: int(2), expect: float64(2.5)},
+		{left: int(-6), right: uint(4), expect: float64(-1.5)},
+		{left: float64(1), right: int(4), expect: float64(0.25)},
+		{left: int(1), right: float64(0.5), expect: float64(2)},
+		{left: int(1), right: int(0), isError: true},
+		{left: float64(1), right: float64(0), isError: true},
+		{left: int(1), right: float64(0), isError: true},
+		{left: "foo", right: int(1), isError: true},
+		{left: int64(math.MinInt64), right: int(1), expect: int64(math.MinInt64)},
+		{left: int64(math.MinInt64), right: int(-1), isError: true},
+	}
+
+	for i, tt := range tests {
+		ret, err := Divide(reflect.ValueOf(tt.left), reflect.ValueOf(tt.right))
+		if tt.isError {
+			if err == nil {
+				t.Errorf("[%d] Expects error but got nil", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Expects no error, got error %s", i, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.expect, ret.Interface()); diff != "" {
+			t.Errorf("[%d] Divide result mismatch, diff=%s", i, diff)
+		}
+	}
+}
+
+func TestArithmeticModulo(t *testing.T) {
+	tests := []struct {
+		left    any
+		right   any
+		expect  any
+		isError bool
+	}{
+		{left: int(7), right: int(2), expect: int64(1)},
+		{left: int(-7), right: int(2), expect: int64(-1)},
+		{left: uint8(8), right: int(4), expect: int64(0)},
+		{left: float64(7.5), right: int(2), expect: float64(1.5)},
+		{left: int(1), right: int(0), isError: true},
+		{left: float64(1), right: float64(0), isError: true},
+		{left: true, right: int(1), isError: true},
+	}
+
+	for i, tt := range tests {
+		ret, err := Modulo(reflect.ValueOf(tt.left), reflect.ValueOf(tt.right))
+		if tt.isError {
+			if err == nil {
+				t.Errorf("[%d] Expects error but got nil", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Expects no error, got error %s", i, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.expect, ret.Interface()); diff != "" {
+			t.Errorf("[%d] Modulo result mismatch, diff=%s", i, diff)
+		}
+	}
+}
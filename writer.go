@@ -0,0 +1,79 @@
+package tender
+
+import (
+	"bytes"
+	"io"
+)
+
+// writer struct streams rendered output to the io.Writer.
+// Control syntax may trim whitespaces around it by "~" marker, but we could not rewrite already written bytes.
+// So trailing whitespaces of literal are held as pending, and they are flushed when following output is written,
+// or discarded when following control trims them.
+type writer struct {
+	w        io.Writer
+	pending  bytes.Buffer
+	trimLeft bool
+}
+
+func newWriter(w io.Writer) *writer {
+	return &writer{w: w}
+}
+
+// Write literal string.
+// Trailing whitespaces are held as pending until following output is written
+func (w *writer) writeLiteral(s string) error {
+	if w.trimLeft {
+		s = trimLeftSpace(s)
+		if s == "" {
+			return nil
+		}
+		w.trimLeft = false
+	}
+
+	body := trimRightSpace(s)
+	if body != "" {
+		if err := w.flush(); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w.w, body); err != nil {
+			return err
+		}
+	}
+	w.pending.WriteString(s[len(body):])
+	return nil
+}
+
+// Write evaluated string like interporation result.
+// This string is not a target of trimming, write as it is
+func (w *writer) writeString(s string) error {
+	if s == "" {
+		return nil
+	}
+	w.trimLeft = false
+
+	if err := w.flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.w, s)
+	return err
+}
+
+// Trim whitespaces which are written before, for "%{~" marker
+func (w *writer) trimRight() {
+	w.pending.Reset()
+}
+
+// Trim whitespaces which will be written after, for "~}" marker
+func (w *writer) trimNextLeft() {
+	w.trimLeft = true
+}
+
+// Flush pending whitespaces to the underlying writer
+func (w *writer) flush() error {
+	if w.pending.Len() == 0 {
+		return nil
+	}
+	_, err := w.w.Write(w.pending.Bytes())
+	w.pending.Reset()
+	return err
+}
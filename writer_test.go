@@ -0,0 +1,89 @@
+package tender
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		write  func(w *writer) error
+		expect string
+	}{
+		{
+			name: "pending whitespaces are flushed by following output",
+			write: func(w *writer) error {
+				if err := w.writeLiteral("foo \n"); err != nil {
+					return err
+				}
+				return w.writeString("bar")
+			},
+			expect: "foo \nbar",
+		},
+		{
+			name: "trim right discards pending whitespaces",
+			write: func(w *writer) error {
+				if err := w.writeLiteral("foo \n"); err != nil {
+					return err
+				}
+				w.trimRight()
+				return w.writeString("bar")
+			},
+			expect: "foobar",
+		},
+		{
+			name: "trim right does not affect to evaluated string",
+			write: func(w *writer) error {
+				if err := w.writeString("foo \n"); err != nil {
+					return err
+				}
+				w.trimRight()
+				return w.writeLiteral("bar")
+			},
+			expect: "foo \nbar",
+		},
+		{
+			name: "trim next left trims following literal",
+			write: func(w *writer) error {
+				w.trimNextLeft()
+				if err := w.writeLiteral(" \n "); err != nil {
+					return err
+				}
+				return w.writeLiteral("\n foo")
+			},
+			expect: "foo",
+		},
+		{
+			name: "trim next left is cancelled by evaluated string",
+			write: func(w *writer) error {
+				w.trimNextLeft()
+				if err := w.writeString("foo"); err != nil {
+					return err
+				}
+				return w.writeLiteral(" bar")
+			},
+			expect: "foo bar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			w := newWriter(buf)
+			if err := tt.write(w); err != nil {
+				t.Errorf("Unexpected write error: %s", err)
+				return
+			}
+			if err := w.flush(); err != nil {
+				t.Errorf("Unexpected flush error: %s", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, buf.String()); diff != "" {
+				t.Errorf("Written string mismatch, diff=%s", diff)
+			}
+		})
+	}
+}